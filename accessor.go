@@ -0,0 +1,225 @@
+package patchpanel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Accessor looks up the values of a populated struct by dotted key, as viper's Get("database.host") does, for
+// codebases moving from stringly-keyed lookups to typed structs.  Keys are field paths (as in FieldError), matched
+// case-insensitively, or the dotted ConfigTag keys of fields.  Entries of maps of structs are reached by their map
+// key, e.g. "workers.email.retries".
+//
+// The typed getters convert where they sensibly can, e.g. GetInt of "8080", and otherwise return the zero value,
+// as viper does; use Get or IsSet to tell a missing key from a zero value.
+type Accessor struct {
+	// values holds each field's value by its lowercased key
+	values map[string]any
+	// keys lists each field's path once, in struct order
+	keys []string
+}
+
+// Accessor snapshots the fields of src, a struct or pointer to one, walked as Populate walks them.  Later changes
+// to src are not seen.
+func (pc *PatchPanel) Accessor(src any) (*Accessor, error) {
+	rv := reflect.ValueOf(src)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, pc.misuse(InvalidTargetError{Msg: fmt.Sprintf("accessor source must be a struct or pointer to one, got %T", src)})
+	}
+	a := &Accessor{values: make(map[string]any)}
+	pc.accessorFields(a, rv, "", map[reflect.Type]bool{})
+	return a, nil
+}
+
+// accessorFields adds the fields of the struct rv at prefix to a.  seen holds the struct types on the current
+// path so that self-referencing types terminate.
+func (pc *PatchPanel) accessorFields(a *Accessor, rv reflect.Value, prefix string, seen map[reflect.Type]bool) {
+	rt := rv.Type()
+	seen[rt] = true
+	defer delete(seen, rt)
+
+	for i := 0; i < rt.NumField(); i++ {
+		sF := rt.Field(i)
+		if !sF.IsExported() && !sF.Anonymous {
+			continue
+		}
+		path := fieldPath(prefix, sF.Name)
+		fieldValue := rv.Field(i)
+
+		if _, ok := pc.parser(sF.Type); !ok {
+			switch {
+			case sF.Type.Kind() == reflect.Struct && !seen[sF.Type]:
+				pc.accessorFields(a, fieldValue, path, seen)
+			case sF.Type.Kind() == reflect.Pointer && sF.Type.Elem().Kind() == reflect.Struct:
+				if !fieldValue.IsNil() && !seen[sF.Type.Elem()] {
+					pc.accessorFields(a, fieldValue.Elem(), path, seen)
+				}
+			case pc.isStructMap(sF.Type):
+				for _, key := range fieldValue.MapKeys() {
+					entry := fieldValue.MapIndex(key)
+					if entry.Kind() == reflect.Pointer {
+						if entry.IsNil() {
+							continue
+						}
+						entry = entry.Elem()
+					}
+					// map entries aren't addressable, so nested fields are read from a copy
+					copied := reflect.New(entry.Type()).Elem()
+					copied.Set(entry)
+					pc.accessorFields(a, copied, fieldPath(path, key.String()), seen)
+				}
+			}
+			continue
+		}
+
+		if !fieldValue.CanInterface() {
+			continue
+		}
+		value := fieldValue.Interface()
+		a.keys = append(a.keys, path)
+		a.values[strings.ToLower(path)] = value
+		if key := sF.Tag.Get(ConfigTag); key != "" {
+			a.values[strings.ToLower(key)] = value
+		}
+	}
+}
+
+// Get returns the value under key, and whether there is one
+func (a *Accessor) Get(key string) (any, bool) {
+	value, ok := a.values[strings.ToLower(key)]
+	return value, ok
+}
+
+// IsSet reports whether key names a field
+func (a *Accessor) IsSet(key string) bool {
+	_, ok := a.Get(key)
+	return ok
+}
+
+// AllKeys lists the path of every field, sorted
+func (a *Accessor) AllKeys() []string {
+	keys := append([]string(nil), a.keys...)
+	sort.Strings(keys)
+	return keys
+}
+
+// GetString returns the value under key as text: strings as they are, and other values formatted with fmt
+func (a *Accessor) GetString(key string) string {
+	value, ok := a.Get(key)
+	if !ok {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// GetBool returns the bool under key, parsing strings with strconv.ParseBool
+func (a *Accessor) GetBool(key string) bool {
+	value, _ := a.Get(key)
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		b, _ := strconv.ParseBool(rv.String())
+		return b
+	}
+	return false
+}
+
+// GetInt returns the integer under key, converting other numbers and parsing strings
+func (a *Accessor) GetInt(key string) int {
+	return int(a.GetInt64(key))
+}
+
+// GetInt64 returns the integer under key, converting other numbers and parsing strings
+func (a *Accessor) GetInt64(key string) int64 {
+	value, _ := a.Get(key)
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.CanInt():
+		return rv.Int()
+	case rv.CanUint():
+		return int64(rv.Uint())
+	case rv.CanFloat():
+		return int64(rv.Float())
+	case rv.Kind() == reflect.String:
+		n, _ := strconv.ParseInt(rv.String(), 0, 64)
+		return n
+	}
+	return 0
+}
+
+// GetFloat64 returns the number under key, converting integers and parsing strings
+func (a *Accessor) GetFloat64(key string) float64 {
+	value, _ := a.Get(key)
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.CanFloat():
+		return rv.Float()
+	case rv.CanInt():
+		return float64(rv.Int())
+	case rv.CanUint():
+		return float64(rv.Uint())
+	case rv.Kind() == reflect.String:
+		f, _ := strconv.ParseFloat(rv.String(), 64)
+		return f
+	}
+	return 0
+}
+
+// GetDuration returns the time.Duration under key, parsing strings with time.ParseDuration
+func (a *Accessor) GetDuration(key string) time.Duration {
+	value, _ := a.Get(key)
+	switch v := value.(type) {
+	case time.Duration:
+		return v
+	case string:
+		d, _ := time.ParseDuration(v)
+		return d
+	}
+	return time.Duration(a.GetInt64(key))
+}
+
+// GetTime returns the time.Time under key, parsing strings in RFC 3339
+func (a *Accessor) GetTime(key string) time.Time {
+	value, _ := a.Get(key)
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		t, _ := time.Parse(time.RFC3339Nano, v)
+		return t
+	}
+	return time.Time{}
+}
+
+// GetStringSlice returns the slice or array under key with each element as text, as GetString formats it
+func (a *Accessor) GetStringSlice(key string) []string {
+	value, _ := a.Get(key)
+	if s, ok := value.([]string); ok {
+		return append([]string(nil), s...)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	// []byte is a value of its own rather than a list
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil
+	}
+	entries := make([]string, rv.Len())
+	for i := range entries {
+		entries[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return entries
+}
@@ -0,0 +1,86 @@
+package patchpanel
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type AccessorConfig struct {
+	Name     string        `default:"billing"`
+	Port     uint16        `default:"8080"`
+	Ratio    float64       `default:"0.5"`
+	Debug    bool          `default:"true"`
+	Timeout  time.Duration `default:"5s"`
+	Started  time.Time     `default:"2025-01-02T03:04:05Z"`
+	Hosts    []string      `default:"a·b"`
+	Ports    []int         `default:"1·2"`
+	Key      []byte        `default:"AQI=" encoding:"base64"`
+	Level    string        `config:"log.level" default:"warn"`
+	Numeric  string        `default:"42"`
+	Database struct {
+		Host string `default:"localhost"`
+	}
+	Replica *PopulateWorker
+	Workers map[string]PopulateWorker
+}
+
+func TestPatchPanel_Accessor(t *testing.T) {
+	var conf AccessorConfig
+	if err := New().Populate(&conf); err != nil {
+		t.Fatal(err)
+	}
+	conf.Workers = map[string]PopulateWorker{"email": {Retries: 7}}
+	accessor, err := New().Accessor(&conf)
+	if err != nil {
+		t.Fatalf("Accessor() error = %v", err)
+	}
+	conf.Name = "changed"
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "string", got: accessor.GetString("name"), want: "billing"},
+		{name: "formatted", got: accessor.GetString("Timeout"), want: "5s"},
+		{name: "nested", got: accessor.GetString("database.host"), want: "localhost"},
+		{name: "config key", got: accessor.GetString("log.level"), want: "warn"},
+		{name: "field path", got: accessor.GetString("Level"), want: "warn"},
+		{name: "int", got: accessor.GetInt("port"), want: 8080},
+		{name: "int from string", got: accessor.GetInt("numeric"), want: 42},
+		{name: "int from float", got: accessor.GetInt64("ratio"), want: int64(0)},
+		{name: "float", got: accessor.GetFloat64("ratio"), want: 0.5},
+		{name: "float from uint", got: accessor.GetFloat64("port"), want: 8080.0},
+		{name: "bool", got: accessor.GetBool("debug"), want: true},
+		{name: "duration", got: accessor.GetDuration("timeout"), want: 5 * time.Second},
+		{name: "time", got: accessor.GetTime("started"), want: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{name: "string slice", got: accessor.GetStringSlice("hosts"), want: []string{"a", "b"}},
+		{name: "int slice", got: accessor.GetStringSlice("ports"), want: []string{"1", "2"}},
+		{name: "bytes are not a list", got: accessor.GetStringSlice("key"), want: []string(nil)},
+		{name: "map entry", got: accessor.GetInt("workers.email.retries"), want: 7},
+		{name: "missing", got: accessor.GetString("nope"), want: ""},
+		{name: "missing int", got: accessor.GetInt("nope"), want: 0},
+		{name: "nil pointer skipped", got: accessor.IsSet("replica.retries"), want: false},
+		{name: "set", got: accessor.IsSet("Database.Host"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %#v, want %#v", tt.got, tt.want)
+			}
+		})
+	}
+
+	if value, ok := accessor.Get("PORT"); !ok || value != uint16(8080) {
+		t.Errorf("Get() = %v, %v", value, ok)
+	}
+	keys := accessor.AllKeys()
+	if len(keys) != 15 || keys[0] != "Database.Host" || keys[len(keys)-1] != "Workers.email.Timeout" {
+		t.Errorf("AllKeys() = %v", keys)
+	}
+
+	if _, err := New(WithDebug(false)).Accessor(42); err == nil {
+		t.Errorf("Accessor() of a non-struct succeeded")
+	}
+}
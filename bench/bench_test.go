@@ -0,0 +1,91 @@
+package bench
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tristanfisher/patchpanel"
+)
+
+// largeConfigType builds a struct type with fields of a representative mix of types, grouped into nested
+// sections as large application configs are
+func largeConfigType(sections, fieldsPerSection int) reflect.Type {
+	kinds := []struct {
+		typ reflect.Type
+		tag string
+	}{
+		{reflect.TypeOf(""), `default:"value"`},
+		{reflect.TypeOf(0), `default:"0x1F"`},
+		{reflect.TypeOf(false), `default:"on" boolStyle:"lenient"`},
+		{reflect.TypeOf(time.Duration(0)), `default:"1d12h" durationUnits:"extended"`},
+		{reflect.TypeOf(time.Time{}), `default:"2024-03-01" timeFormat:"RFC3339·DateOnly"`},
+		{reflect.TypeOf(patchpanel.ByteSize(0)), `default:"512MiB"`},
+		{reflect.TypeOf(netip.Prefix{}), `default:"10.0.0.0/8"`},
+		{reflect.TypeOf([]int{}), `default:"1,2,3" sep:","`},
+	}
+
+	sectionFields := make([]reflect.StructField, fieldsPerSection)
+	for i := range sectionFields {
+		kind := kinds[i%len(kinds)]
+		sectionFields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Field%d", i),
+			Type: kind.typ,
+			Tag:  reflect.StructTag(kind.tag),
+		}
+	}
+	section := reflect.StructOf(sectionFields)
+
+	fields := make([]reflect.StructField, sections)
+	for i := range fields {
+		fields[i] = reflect.StructField{Name: fmt.Sprintf("Section%d", i), Type: section}
+	}
+	return reflect.StructOf(fields)
+}
+
+type request struct {
+	ID      patchpanel.UUID `default:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+	Limit   int             `default:"100"`
+	Offset  int             `default:"0"`
+	Verbose bool            `default:"yes" boolStyle:"lenient"`
+	Since   time.Time       `default:"2024-03-01" timeFormat:"RFC3339·DateOnly"`
+	Tags    []string        `default:"a,b,c" sep:","`
+}
+
+func BenchmarkLargeConfigLoad(b *testing.B) {
+	pp := patchpanel.New()
+	typ := largeConfigType(20, 25)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := pp.Populate(reflect.New(typ).Interface()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRequestBinding(b *testing.B) {
+	pp := patchpanel.New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var dst request
+		if err := pp.Populate(&dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReloadStorm(b *testing.B) {
+	pp := patchpanel.New()
+	typ := largeConfigType(20, 25)
+	b.ReportAllocs()
+	b.SetParallelism(8)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := pp.Populate(reflect.New(typ).Interface()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
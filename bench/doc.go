@@ -0,0 +1,15 @@
+// Package bench holds representative patchpanel workloads for benchmarking and for generating profiles for
+// profile-guided optimization (PGO).
+//
+// The workloads are:
+//
+//	BenchmarkLargeConfigLoad  populating a configuration struct with several hundred tagged fields
+//	BenchmarkRequestBinding   populating a small per-request struct, as when binding request parameters
+//	BenchmarkReloadStorm      many goroutines re-populating the large configuration at once
+//
+// To produce a profile for an application that embeds patchpanel, run the workloads closest to its use and
+// merge the result into the application's default.pgo:
+//
+//	go test ./bench -run '^$' -bench . -cpuprofile cpu.pprof
+//	go tool pprof -proto cpu.pprof app.pprof > default.pgo
+package bench
@@ -0,0 +1,166 @@
+package patchpanel
+
+import (
+	"container/list"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+	"unique"
+)
+
+// DefaultCacheSize is the capacity of the LRU cache used by WithCache when no cache is supplied
+const DefaultCacheSize = 1024
+
+// CacheKey identifies a coerced value: the destination type, the raw value, and a hash of the parser hints
+type CacheKey struct {
+	Type  reflect.Type
+	Raw   string
+	Hints uint64
+}
+
+// Cache stores the output of expensive parsers, such as regexp compilation or certificate loading, so that
+// repeated values are parsed once.  Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key CacheKey) (any, bool)
+	Add(key CacheKey, val any)
+	// Purge empties the cache; it is called whenever the parser registry changes
+	Purge()
+}
+
+// LRUCache is a Cache holding a fixed number of values, evicting the least recently used
+type LRUCache struct {
+	size    int
+	order   *list.List
+	entries map[CacheKey]*list.Element
+	mu      sync.Mutex
+}
+
+type lruEntry struct {
+	key CacheKey
+	val any
+}
+
+// NewLRUCache creates an LRUCache holding up to size values
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+	return &LRUCache{size: size, order: list.New(), entries: make(map[CacheKey]*list.Element)}
+}
+
+func (c *LRUCache) Get(key CacheKey) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).val, true
+}
+
+func (c *LRUCache) Add(key CacheKey, val any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).val = val
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, val: val})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *LRUCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+}
+
+// Len is the number of cached values
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// hashHints hashes parser hints independently of map iteration order
+func hashHints(parserHints Hints) uint64 {
+	keys := make([]string, 0, len(parserHints))
+	for key := range parserHints {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	h := fnv.New64a()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s\x00%v\x00", key, parserHints[key])
+	}
+	return h.Sum64()
+}
+
+// defaultCachedTypes are cached by WithCache when no types are given: those whose parsers are expensive and
+// whose values are safe to share
+var defaultCachedTypes = []reflect.Type{
+	reflect.TypeOf(&regexp.Regexp{}),
+}
+
+// internedTypes are the immutable parsed values WithInterning shares between fields
+var internedTypes = []reflect.Type{
+	reflect.TypeOf(&regexp.Regexp{}),
+	reflect.TypeOf(time.UTC),
+	reflect.TypeOf(url.URL{}),
+}
+
+// parse runs parserFunc for a value of type typ, consulting the panel's cache for cacheable types and interning
+// strings when configured to.  The result is checked against the field's constraint hints, and failures are
+// rewritten by the panel's MessageCatalog and carry the field's `errmsg` tag when it has one.
+func (pc *PatchPanel) parse(parserFunc HintsParser, v string, typ reflect.Type, parserHints Hints) (any, error) {
+	val, err := pc.parseCached(parserFunc, v, typ, parserHints)
+	if err == nil {
+		err = pc.validate(val, typ, parserHints)
+	}
+	if err != nil && pc.messages != nil {
+		if msg := pc.messages(errorCode(err), err); msg != "" {
+			err = LocalizedError{Msg: msg, Err: err}
+		}
+	}
+	if err != nil && parserHints.Has("errmsg") {
+		if msg, hintErr := parserHints.GetString("errmsg"); hintErr == nil {
+			err = MessageError{Msg: msg, Err: err}
+		}
+	}
+	return val, err
+}
+
+func (pc *PatchPanel) parseCached(parserFunc HintsParser, v string, typ reflect.Type, parserHints Hints) (any, error) {
+	if pc.cache == nil || !pc.cachedTypes[typ] {
+		val, err := parserFunc(v, parserHints)
+		if pc.intern && err == nil && typ.Kind() == reflect.String {
+			if s, ok := val.(string); ok {
+				val = unique.Make(s).Value()
+			}
+		}
+		return val, err
+	}
+
+	key := CacheKey{Type: typ, Raw: v, Hints: hashHints(parserHints)}
+	if val, ok := pc.cache.Get(key); ok {
+		return val, nil
+	}
+	val, err := parserFunc(v, parserHints)
+	if err == nil {
+		pc.cache.Add(key, val)
+	}
+	return val, err
+}
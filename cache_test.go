@@ -0,0 +1,129 @@
+package patchpanel
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	a, b, d := CacheKey{Raw: "a"}, CacheKey{Raw: "b"}, CacheKey{Raw: "d"}
+
+	c.Add(a, 1)
+	c.Add(b, 2)
+	if _, ok := c.Get(a); !ok {
+		t.Fatal("Get(a) missing")
+	}
+	// b is now least recently used
+	c.Add(d, 3)
+	if _, ok := c.Get(b); ok {
+		t.Errorf("Get(b) present after eviction")
+	}
+	if val, ok := c.Get(a); !ok || val != 1 {
+		t.Errorf("Get(a) = %v, %v", val, ok)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Len() after Purge = %d", c.Len())
+	}
+}
+
+func TestHashHints(t *testing.T) {
+	a := Hints{"base": "16", "unit": "bytes"}
+	b := Hints{"unit": "bytes", "base": "16"}
+	if hashHints(a) != hashHints(b) {
+		t.Errorf("hashHints() depends on insertion order")
+	}
+	if hashHints(a) == hashHints(Hints{"base": "8", "unit": "bytes"}) {
+		t.Errorf("hashHints() ignores values")
+	}
+}
+
+type CacheStruct struct {
+	First  *regexp.Regexp `default:"^[a-z]+$"`
+	Second *regexp.Regexp `default:"^[a-z]+$"`
+	Posix  *regexp.Regexp `default:"^[a-z]+$" regexpMode:"posix"`
+	Count  int            `default:"1"`
+}
+
+func TestWithCache(t *testing.T) {
+	cache := NewLRUCache(8)
+	pp := New(WithCache(cache))
+
+	var dst CacheStruct
+	if err := pp.Populate(&dst); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if dst.First != dst.Second {
+		t.Errorf("Populate() compiled the same pattern twice")
+	}
+	if dst.First == dst.Posix {
+		t.Errorf("Populate() shared a value across different hints")
+	}
+	// ints are not cached by default
+	if cache.Len() != 2 {
+		t.Errorf("cache holds %d values, want 2", cache.Len())
+	}
+
+	// registry changes invalidate the cache
+	pp.AddHintsParser(reflect.TypeOf(Port(0)), func(value string, parserHints Hints) (any, error) {
+		return Port(0), nil
+	})
+	if cache.Len() != 0 {
+		t.Errorf("cache holds %d values after AddParser, want 0", cache.Len())
+	}
+
+	// getters use the cache too
+	first, _ := pp.GetDefault("First", ToReflectType(CacheStruct{}), nil)
+	second, _ := pp.GetDefault("Second", ToReflectType(CacheStruct{}), nil)
+	if first != second {
+		t.Errorf("GetDefault() compiled the same pattern twice")
+	}
+
+	// nil uses the default LRU
+	if pp := New(WithCache(nil, reflect.TypeOf(0))); pp.cache == nil || !pp.cachedTypes[reflect.TypeOf(0)] {
+		t.Errorf("WithCache(nil, int) not configured")
+	}
+}
+
+type InternStruct struct {
+	Tenant  string         `default:"acme\tcorp"`
+	Owner   string         `default:"acme\tcorp"`
+	Zone    *time.Location `default:"America/Chicago"`
+	Home    *time.Location `default:"America/Chicago"`
+	Pattern *regexp.Regexp `default:"^x$"`
+}
+
+func TestWithInterning(t *testing.T) {
+	pp := New(WithInterning())
+
+	a, b := InternStruct{}, InternStruct{}
+	if err := pp.Populate(&a); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if err := pp.Populate(&b); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+
+	// unquoting the escaped tag allocates each time, so shared data means the strings were interned
+	if unsafe.StringData(a.Tenant) != unsafe.StringData(b.Owner) {
+		t.Errorf("Populate() did not intern equal strings")
+	}
+	if a.Zone != b.Home || a.Pattern != b.Pattern {
+		t.Errorf("Populate() did not share parsed values across instances")
+	}
+
+	// interning and caching combine
+	cache := NewLRUCache(8)
+	pp = New(WithCache(cache, reflect.TypeOf(0)), WithInterning())
+	if pp.cache != cache || !pp.cachedTypes[reflect.TypeOf(0)] || !pp.cachedTypes[reflect.TypeOf(time.UTC)] {
+		t.Errorf("WithInterning() replaced the configured cache")
+	}
+}
@@ -0,0 +1,246 @@
+package patchpanel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigOption sets fields of a configuration struct T, for libraries offering a functional options API over the
+// same struct they populate.  Options are built with SetField, or generated with GenerateOptions, and applied
+// with BuildConfig.
+type ConfigOption[T any] func(pc *PatchPanel, conf *T) error
+
+// SetField returns an option setting the field at the dotted path (as in FieldError) to value.  A value of the
+// field's type is checked against the field's constraint hints, as a parsed value would be; a string for a field
+// of another type is parsed with the field's parser and hints, as if it were its value tag.  Nil pointers to
+// structs along the path are allocated.
+func SetField[T any](path string, value any) ConfigOption[T] {
+	return func(pc *PatchPanel, conf *T) error {
+		sF, err := pc.setField(reflect.ValueOf(conf).Elem(), path, value)
+		if err != nil {
+			return pc.fieldError(sF, path, fmt.Sprint(value), SourceOption, err)
+		}
+		return nil
+	}
+}
+
+// BuildConfig returns a T populated by pc with opts applied over it in order.  Failures, of population or of any
+// option, are joined into the returned error.
+func BuildConfig[T any](pc *PatchPanel, opts ...ConfigOption[T]) (T, error) {
+	var conf T
+	err := pc.Populate(&conf)
+	errs := []error{err}
+	for _, opt := range opts {
+		errs = append(errs, opt(pc, &conf))
+	}
+	return conf, errors.Join(errs...)
+}
+
+// setField sets the field at path in rv to value, returning the field, which is zero if path doesn't name one
+func (pc *PatchPanel) setField(rv reflect.Value, path string, value any) (reflect.StructField, error) {
+	var sF reflect.StructField
+	if rv.Kind() != reflect.Struct {
+		return sF, pc.misuse(InvalidTargetError{Msg: fmt.Sprintf("option target must be a struct, got %v", rv.Type())})
+	}
+	for segment := range strings.SplitSeq(path, ".") {
+		if rv.Kind() == reflect.Pointer && rv.Type().Elem().Kind() == reflect.Struct {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return sF, NoFieldError{Msg: fmt.Sprintf("no field %s", path)}
+		}
+		var ok bool
+		if sF, ok = rv.Type().FieldByName(segment); !ok || !sF.IsExported() {
+			return sF, NoFieldError{Msg: fmt.Sprintf("no field %s", path)}
+		}
+		rv = rv.FieldByIndex(sF.Index)
+	}
+
+	parserFunc, ok := pc.parser(sF.Type)
+	if !ok {
+		return sF, UnhandledParserTypeError{Msg: fmt.Sprintf("unknown type for parser: %v", sF.Type)}
+	}
+	hints := fieldHints(sF)
+	if raw, isString := value.(string); isString && sF.Type.Kind() != reflect.String {
+		parsed, err := pc.parse(parserFunc, raw, sF.Type, hints)
+		if err != nil {
+			return sF, err
+		}
+		return sF, assign(rv, parsed)
+	}
+
+	// as with parser output, a value of the underlying type (e.g. an int for a `type Port int`) is converted
+	val := reflect.ValueOf(value)
+	if !val.IsValid() || val.Kind() != sF.Type.Kind() || !val.Type().ConvertibleTo(sF.Type) {
+		return sF, fmt.Errorf("cannot set %v to %T", sF.Type, value)
+	}
+	typed := val.Convert(sF.Type).Interface()
+	if err := pc.validate(typed, sF.Type, hints); err != nil {
+		return sF, err
+	}
+	return sF, assign(rv, typed)
+}
+
+// GenerateOptions writes Go source declaring a ConfigOption for each field Populate would set in the struct type
+// t, such as WithPort(v int) for a Port field and WithDatabaseHost(v string) for Database.Host, so that a library
+// can offer both APIs from one struct.  The source is in package pkg, which must be t's package:
+//
+//	//go:generate go run ./internal/genoptions
+//
+// with a program writing New().GenerateOptions(file, reflect.TypeFor[Config](), "mylib").  Fields of embedded
+// structs are promoted, as in Go.
+func (pc *PatchPanel) GenerateOptions(w io.Writer, t reflect.Type, pkg string) error {
+	if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+		return pc.misuse(InvalidTargetError{Msg: fmt.Sprintf("options target must be a named struct type, got %v", t)})
+	}
+
+	gen := optionGenerator{
+		pc:       pc,
+		pkgPath:  t.PkgPath(),
+		typeName: t.Name(),
+		imports:  map[string]bool{"github.com/tristanfisher/patchpanel": true},
+		names:    make(map[string]string),
+		seen:     make(map[reflect.Type]bool),
+	}
+	if err := gen.fields(t, "", ""); err != nil {
+		return err
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by patchpanel GenerateOptions; DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	// standard library imports first, as goimports groups them
+	var std, other []string
+	for path := range gen.imports {
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for _, path := range std {
+		fmt.Fprintf(&src, "\t%q\n", path)
+	}
+	if len(std) > 0 {
+		fmt.Fprintf(&src, "\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(&src, "\t%q\n", path)
+	}
+	fmt.Fprintf(&src, ")\n")
+	for _, decl := range gen.decls {
+		fmt.Fprintf(&src, "\n%s", decl)
+	}
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated options: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// optionGenerator collects the option declarations for a struct type
+type optionGenerator struct {
+	pc       *PatchPanel
+	typeName string
+	pkgPath  string
+	// imports holds the import paths of the packages the generated source refers to
+	imports map[string]bool
+	// names maps each option's name to its field's path, to report collisions
+	names map[string]string
+	decls []string
+	seen  map[reflect.Type]bool
+}
+
+// fields adds options for the fields of rt, at prefix and named with namePrefix
+func (gen *optionGenerator) fields(rt reflect.Type, prefix string, namePrefix string) error {
+	gen.seen[rt] = true
+	defer delete(gen.seen, rt)
+
+	for i := 0; i < rt.NumField(); i++ {
+		sF := rt.Field(i)
+		if !sF.IsExported() {
+			continue
+		}
+		path := fieldPath(prefix, sF.Name)
+		name := namePrefix + sF.Name
+		if sF.Anonymous {
+			name = namePrefix
+		}
+
+		if _, ok := gen.pc.parser(sF.Type); !ok {
+			nested := sF.Type
+			if nested.Kind() == reflect.Pointer {
+				nested = nested.Elem()
+			}
+			if nested.Kind() == reflect.Struct && !gen.seen[nested] {
+				if err := gen.fields(nested, path, name); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		name = "With" + name
+		if other, taken := gen.names[name]; taken {
+			return fmt.Errorf("fields %s and %s both generate option %s", other, path, name)
+		}
+		gen.names[name] = path
+		typeExpr, err := gen.typeExpr(sF.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", path, err)
+		}
+
+		doc := fmt.Sprintf("// %s sets %s.", name, path)
+		if raw, ok := gen.pc.lookupValueTag(sF); ok {
+			doc = fmt.Sprintf("// %s sets %s, which defaults to %q.", name, path, raw)
+		}
+		gen.decls = append(gen.decls, fmt.Sprintf("%s\nfunc %s(v %s) patchpanel.ConfigOption[%s] {\n\treturn patchpanel.SetField[%s](%q, v)\n}\n",
+			doc, name, typeExpr, gen.typeName, gen.typeName, path))
+	}
+	return nil
+}
+
+// typeExpr is the Go expression for typ in the generated package, recording the imports it needs
+func (gen *optionGenerator) typeExpr(typ reflect.Type) (string, error) {
+	if typ.Name() != "" {
+		if typ.PkgPath() == "" || typ.PkgPath() == gen.pkgPath {
+			return typ.Name(), nil
+		}
+		pkgName, _, _ := strings.Cut(typ.String(), ".")
+		gen.imports[typ.PkgPath()] = true
+		return pkgName + "." + typ.Name(), nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		elem, err := gen.typeExpr(typ.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := gen.typeExpr(typ.Elem())
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := gen.typeExpr(typ.Elem())
+		return fmt.Sprintf("[%d]%s", typ.Len(), elem), err
+	case reflect.Map:
+		key, err := gen.typeExpr(typ.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := gen.typeExpr(typ.Elem())
+		return fmt.Sprintf("map[%s]%s", key, elem), err
+	default:
+		return "", fmt.Errorf("cannot write the unnamed type %v", typ)
+	}
+}
@@ -0,0 +1,119 @@
+package patchpanel
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type OptionEmbedded struct {
+	Region string `default:"us-east-1"`
+}
+
+type OptionConfig struct {
+	OptionEmbedded
+	Port     int           `default:"8080" port:"true"`
+	Timeout  time.Duration `default:"5s"`
+	Endpoint *url.URL
+	Tags     []string
+	Database struct {
+		Host string `default:"localhost"`
+	}
+	Worker  *PopulateWorker
+	Workers map[string]PopulateWorker
+}
+
+func TestSetField(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		value   any
+		check   func(OptionConfig) bool
+		wantErr bool
+	}{
+		{name: "typed", path: "Port", value: 9090, check: func(c OptionConfig) bool { return c.Port == 9090 }},
+		{name: "parsed string", path: "Timeout", value: "1m", check: func(c OptionConfig) bool { return c.Timeout == time.Minute }},
+		{name: "nested", path: "Database.Host", value: "db1", check: func(c OptionConfig) bool { return c.Database.Host == "db1" }},
+		{name: "promoted", path: "Region", value: "eu-west-1", check: func(c OptionConfig) bool { return c.Region == "eu-west-1" }},
+		{name: "nil pointer allocated", path: "Worker.Retries", value: 5, check: func(c OptionConfig) bool { return c.Worker != nil && c.Worker.Retries == 5 }},
+		{name: "validated", path: "Port", value: 70000, wantErr: true},
+		{name: "validated string", path: "Worker.Concurrency", value: "0", wantErr: true},
+		{name: "wrong type", path: "Port", value: 1.5, wantErr: true},
+		{name: "nil", path: "Port", value: nil, wantErr: true},
+		{name: "no such field", path: "Database.Name", value: "x", wantErr: true},
+		{name: "not a struct", path: "Port.Value", value: 1, wantErr: true},
+		{name: "no parser", path: "Workers", value: map[string]PopulateWorker{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conf OptionConfig
+			err := SetField[OptionConfig](tt.path, tt.value)(New(), &conf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var fieldErr FieldError
+			if tt.wantErr && (!errors.As(err, &fieldErr) || fieldErr.Field != tt.path || fieldErr.Source != SourceOption) {
+				t.Errorf("SetField() error = %#v, want a FieldError for %s", err, tt.path)
+			}
+			if !tt.wantErr && !tt.check(conf) {
+				t.Errorf("SetField() = %+v", conf)
+			}
+		})
+	}
+}
+
+func TestBuildConfig(t *testing.T) {
+	conf, err := BuildConfig(New(), SetField[OptionConfig]("Port", 9090), SetField[OptionConfig]("Tags", []string{"a"}))
+	if err != nil {
+		t.Fatalf("BuildConfig() error = %v", err)
+	}
+	if conf.Port != 9090 || conf.Timeout != 5*time.Second || conf.Database.Host != "localhost" || !reflect.DeepEqual(conf.Tags, []string{"a"}) {
+		t.Errorf("BuildConfig() = %+v", conf)
+	}
+
+	if _, err := BuildConfig(New(), SetField[OptionConfig]("Port", -1), SetField[OptionConfig]("Nope", 1)); err == nil {
+		t.Errorf("BuildConfig() with failing options succeeded")
+	} else if got := strings.Count(err.Error(), "\n") + 1; got != 2 {
+		t.Errorf("BuildConfig() error = %v, want 2 joined errors", err)
+	}
+}
+
+func TestPatchPanel_GenerateOptions(t *testing.T) {
+	var src strings.Builder
+	if err := New().GenerateOptions(&src, reflect.TypeFor[OptionConfig](), "patchpanel"); err != nil {
+		t.Fatalf("GenerateOptions() error = %v", err)
+	}
+	for _, want := range []string{
+		"// Code generated by patchpanel GenerateOptions; DO NOT EDIT.",
+		"\t\"net/url\"\n",
+		"\t\"time\"\n\n\t\"github.com/tristanfisher/patchpanel\"\n",
+		"// WithRegion sets OptionEmbedded.Region, which defaults to \"us-east-1\".\nfunc WithRegion(v string) patchpanel.ConfigOption[OptionConfig] {",
+		"return patchpanel.SetField[OptionConfig](\"OptionEmbedded.Region\", v)",
+		"func WithTimeout(v time.Duration) patchpanel.ConfigOption[OptionConfig]",
+		"func WithEndpoint(v *url.URL) patchpanel.ConfigOption[OptionConfig]",
+		"// WithTags sets Tags.\nfunc WithTags(v []string)",
+		"func WithDatabaseHost(v string)",
+		"func WithWorkerTimeout(v time.Duration)",
+	} {
+		if !strings.Contains(src.String(), want) {
+			t.Errorf("GenerateOptions() missing %q in\n%s", want, src.String())
+		}
+	}
+	if strings.Contains(src.String(), "WithWorkers") {
+		t.Errorf("GenerateOptions() generated an option for a map of structs")
+	}
+
+	type Collision struct {
+		DatabaseHost string
+		Database     struct{ Host string }
+	}
+	if err := New().GenerateOptions(&src, reflect.TypeFor[Collision](), "patchpanel"); err == nil {
+		t.Errorf("GenerateOptions() with colliding names succeeded")
+	}
+	if err := New(WithDebug(false)).GenerateOptions(&src, reflect.TypeFor[int](), "patchpanel"); err == nil {
+		t.Errorf("GenerateOptions() of a non-struct succeeded")
+	}
+}
@@ -0,0 +1,110 @@
+package patchpanel
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DockerSecretsDir is where Docker and Docker Swarm mount secrets
+const DockerSecretsDir = "/run/secrets"
+
+// dockerSecretsDir is a variable so tests can point it elsewhere
+var dockerSecretsDir = DockerSecretsDir
+
+// DockerSecret reads the secret name from DockerSecretsDir.  A single trailing newline is removed.
+func DockerSecret(name string) (string, error) {
+	return readSecretFile(dockerSecretsDir, name)
+}
+
+// readSecretFile reads the file name directly within dir, removing a single trailing newline
+func readSecretFile(dir, name string) (string, error) {
+	if name == "" || strings.ContainsRune(name, filepath.Separator) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+	contents, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(contents), "\n"), nil
+}
+
+// SecretsDirSource is a Source reading each field from the file in dir named by mapper, as Docker and Kubernetes
+// mount secrets one per file; a nil mapper uses SnakeCaseNames:
+//
+//	secrets := patchpanel.SecretsDirSource(patchpanel.DockerSecretsDir, nil)
+//	err := pc.PopulateFrom(&conf, patchpanel.EnvSource(), secrets)
+//
+// Fields without a file fall through to later sources.  A single trailing newline is removed from each value, and
+// values are reported as SourceSecret.
+func SecretsDirSource(dir string, mapper NameMapper) Source {
+	return dirSource(SourceSecret, dir, mapper)
+}
+
+// dirSource is a Source over the files of dir, one value per file, as secrets and credentials are mounted
+func dirSource(name, dir string, mapper NameMapper) Source {
+	if mapper == nil {
+		mapper = SnakeCaseNames
+	}
+	return mappedSource{name: name, mapper: mapper, lookup: func(file string) (string, bool, error) {
+		value, err := readSecretFile(dir, file)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+		return value, err == nil, err
+	}}
+}
+
+// ReadLabelsFile reads container labels or annotations projected into a file, such as a Kubernetes downward API
+// volume, where each line is key="value" with the value quoted as a Go string.
+func ReadLabelsFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	labels := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		key, quoted, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key=\"value\"", path, line)
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid quoted value: %w", path, line, err)
+		}
+		labels[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// LabelsFileSource reads a labels file, as ReadLabelsFile does, into a Source reading each field from the label
+// named by mapper, e.g. TagNames("label") for `label:"app.kubernetes.io/version"`; a nil mapper uses
+// SnakeCaseNames.  Values are reported as SourceLabel.
+func LabelsFileSource(path string, mapper NameMapper) (Source, error) {
+	labels, err := ReadLabelsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if mapper == nil {
+		mapper = SnakeCaseNames
+	}
+	return mappedSource{name: SourceLabel, mapper: mapper, lookup: func(name string) (string, bool, error) {
+		value, ok := labels[name]
+		return value, ok, nil
+	}}, nil
+}
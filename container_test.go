@@ -0,0 +1,130 @@
+package patchpanel
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDockerSecret(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "api_key"), []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	previous := dockerSecretsDir
+	dockerSecretsDir = dir
+	defer func() { dockerSecretsDir = previous }()
+
+	if got, err := DockerSecret("api_key"); err != nil || got != "s3cr3t" {
+		t.Errorf("DockerSecret() = %q, %v", got, err)
+	}
+	if _, err := DockerSecret("missing"); err == nil {
+		t.Errorf("DockerSecret(missing) expected error")
+	}
+	if _, err := DockerSecret(".."); err == nil {
+		t.Errorf("DockerSecret(..) expected error")
+	}
+}
+
+func TestReadLabelsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels")
+	contents := "app=\"billing\"\ntier=\"backend\"\n\nnote=\"line one\\nline two\"\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadLabelsFile(path)
+	if err != nil {
+		t.Fatalf("ReadLabelsFile() error = %v", err)
+	}
+	want := map[string]string{"app": "billing", "tier": "backend", "note": "line one\nline two"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadLabelsFile() = %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("app=billing\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadLabelsFile(path); err == nil {
+		t.Errorf("ReadLabelsFile() expected error for unquoted value")
+	}
+}
+
+func TestSnakeCaseNames(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "Port", want: "port"},
+		{path: "Database.Password", want: "database_password"},
+		{path: "APIKey", want: "api_key"},
+		{path: "Database.PasswordURL", want: "database_password_url"},
+		{path: "TLS.CertFile2", want: "tls_cert_file2"},
+	}
+	for _, tt := range tests {
+		if got := SnakeCaseNames(reflect.StructField{}, tt.path); got != tt.want {
+			t.Errorf("SnakeCaseNames(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+type ContainerConfig struct {
+	APIKey   string `default:"none"`
+	Database struct {
+		Password string
+	}
+	Version string `label:"app.kubernetes.io/version" default:"dev"`
+	Team    string `label:"team"`
+}
+
+func TestSecretsDirSource(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{"api_key": "s3cr3t\n", "database_password": "hunter2"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var conf ContainerConfig
+	if err := New().PopulateFrom(&conf, SecretsDirSource(dir, nil)); err != nil {
+		t.Fatalf("PopulateFrom(SecretsDirSource()) error = %v", err)
+	}
+	if conf.APIKey != "s3cr3t" || conf.Database.Password != "hunter2" || conf.Version != "dev" {
+		t.Errorf("PopulateFrom(SecretsDirSource()) = %+v", conf)
+	}
+
+	// a mapper can rename fields, and names escaping the directory are rejected
+	renamed := SecretsDirSource(dir, func(_ reflect.StructField, path string) string {
+		if path == "APIKey" {
+			return "../api_key"
+		}
+		return ""
+	})
+	var fieldErr FieldError
+	if err := New().PopulateFrom(&conf, renamed); !errors.As(err, &fieldErr) || fieldErr.Source != SourceSecret {
+		t.Errorf("PopulateFrom() error = %v, want a secret FieldError", err)
+	}
+}
+
+func TestLabelsFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels")
+	if err := os.WriteFile(path, []byte("app.kubernetes.io/version=\"1.4.2\"\nteam=\"payments\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src, err := LabelsFileSource(path, TagNames("label"))
+	if err != nil {
+		t.Fatalf("LabelsFileSource() error = %v", err)
+	}
+	var conf ContainerConfig
+	if err := New().PopulateFrom(&conf, src); err != nil {
+		t.Fatalf("PopulateFrom(LabelsFileSource()) error = %v", err)
+	}
+	if conf.Version != "1.4.2" || conf.Team != "payments" || conf.APIKey != "none" {
+		t.Errorf("PopulateFrom(LabelsFileSource()) = %+v", conf)
+	}
+	if _, err := LabelsFileSource(path+".missing", nil); err == nil {
+		t.Errorf("LabelsFileSource() of a missing file succeeded")
+	}
+}
@@ -3,6 +3,7 @@ package patchpanel
 import (
 	"flag"
 	"os"
+	"path/filepath"
 )
 
 const ENV_CONFIG_FILE = "CONFIG_FILE"
@@ -18,3 +19,59 @@ func GetFileEnvOrPath(envKey string, flagKey string) string {
 	}
 	return valueFilePath
 }
+
+// configExtensions are the configuration file extensions FindConfigFile looks for, in order of preference; each
+// can be read with FileSource
+var configExtensions = []string{".toml", ".json", ".hcl", ".ini", ".properties", ".xml"}
+
+// FindConfigFile searches the conventional locations for appName's configuration, returning every file found in
+// order of precedence: the working directory, $XDG_CONFIG_HOME/appName, the platform's user configuration
+// directory (os.UserConfigDir, e.g. %AppData% on Windows), ~/.config/appName and /etc/appName.  In the working
+// directory it looks for appName with each known extension (e.g. myapp.toml); elsewhere, for config and appName
+// with each extension.  A file found under several names, such as through an XDG_CONFIG_HOME of ~/.config or a
+// differently cased name on a case-insensitive filesystem, is returned once.
+func FindConfigFile(appName string) []string {
+	var dirs []string
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		dirs = append(dirs, filepath.Join(xdg, appName))
+	}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(configDir, appName))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config", appName))
+	}
+	dirs = append(dirs, filepath.Join("/etc", appName))
+
+	var found []string
+	var infos []os.FileInfo
+	add := func(path string) {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			return
+		}
+		for _, seen := range infos {
+			if os.SameFile(seen, info) {
+				return
+			}
+		}
+		found = append(found, path)
+		infos = append(infos, info)
+	}
+	for _, ext := range configExtensions {
+		add(appName + ext)
+	}
+	searched := make(map[string]bool)
+	for _, dir := range dirs {
+		if searched[dir] {
+			continue
+		}
+		searched[dir] = true
+		for _, name := range []string{"config", appName} {
+			for _, ext := range configExtensions {
+				add(filepath.Join(dir, name+ext))
+			}
+		}
+	}
+	return found
+}
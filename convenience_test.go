@@ -0,0 +1,59 @@
+package patchpanel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindConfigFile(t *testing.T) {
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+	home := filepath.Join(root, "home")
+	xdg := filepath.Join(root, "xdg")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	files := []string{
+		filepath.Join(work, "myapp.json"),
+		filepath.Join(work, "myapp.toml"),
+		filepath.Join(work, "config.toml"), // too generic to pick up from the working directory
+		filepath.Join(xdg, "myapp", "config.hcl"),
+		filepath.Join(home, ".config", "myapp", "myapp.ini"),
+		filepath.Join(home, ".config", "myapp", "config.yaml"), // unknown format
+		filepath.Join(home, ".config", "other", "config.toml"),
+	}
+	for _, path := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a directory with a config name is not a match
+	if err := os.MkdirAll(filepath.Join(xdg, "myapp", "config.json"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(work)
+
+	want := []string{
+		"myapp.toml",
+		"myapp.json",
+		filepath.Join(xdg, "myapp", "config.hcl"),
+		filepath.Join(home, ".config", "myapp", "myapp.ini"),
+	}
+	if got := FindConfigFile("myapp"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindConfigFile() = %v, want %v", got, want)
+	}
+
+	// XDG_CONFIG_HOME pointing at ~/.config is searched once
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	if got := FindConfigFile("other"); !reflect.DeepEqual(got, []string{filepath.Join(home, ".config", "other", "config.toml")}) {
+		t.Errorf("FindConfigFile() = %v", got)
+	}
+	if got := FindConfigFile("missing"); got != nil {
+		t.Errorf("FindConfigFile() = %v, want none", got)
+	}
+}
@@ -0,0 +1,27 @@
+package patchpanel
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// misuse reports a programmer error, such as passing a non-pointer to Populate.  In debug mode (see WithDebug)
+// it panics, so that the mistake surfaces with a stack trace during development; otherwise err is returned.
+func (pc *PatchPanel) misuse(err error) error {
+	if pc.debug {
+		panic(fmt.Sprintf("patchpanel: %v", err))
+	}
+	return err
+}
+
+// checkUnexported panics in debug mode when the unexported field sF of the struct at prefix has a value tag,
+// which Populate cannot honor.  Outside of debug mode such fields are skipped, as they always have been.
+func (pc *PatchPanel) checkUnexported(sF reflect.StructField, prefix string) {
+	if !pc.debug {
+		return
+	}
+	if raw, ok := pc.lookupValueTag(sF); ok {
+		panic(fmt.Sprintf("patchpanel: field %s of %s is unexported and can't be set to %s:%q",
+			fieldPath(prefix, sF.Name), sF.Type, pc.valueTag, raw))
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !patchpanel_debug
+
+package patchpanel
+
+// debugBuild is the default for WithDebug; build with -tags patchpanel_debug to enable debug mode everywhere
+const debugBuild = false
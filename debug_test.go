@@ -0,0 +1,89 @@
+package patchpanel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type DebugUnexported struct {
+	Name  string `default:"app"`
+	level string `default:"info"`
+}
+
+func TestWithDebug(t *testing.T) {
+	tests := []struct {
+		name      string
+		debug     bool
+		call      func(pp *PatchPanel) error
+		wantPanic string
+		wantErr   bool
+	}{
+		{
+			name:    "non-pointer target returns an error",
+			call:    func(pp *PatchPanel) error { return pp.Populate(PopulateStruct{}) },
+			wantErr: true,
+		},
+		{
+			name:      "non-pointer target panics in debug mode",
+			debug:     true,
+			call:      func(pp *PatchPanel) error { return pp.Populate(PopulateStruct{}) },
+			wantPanic: "got patchpanel.PopulateStruct",
+		},
+		{
+			name:      "schema of a non-struct panics in debug mode",
+			debug:     true,
+			call:      func(pp *PatchPanel) error { _, err := pp.Schema(42); return err },
+			wantPanic: "schema target must be a struct",
+		},
+		{
+			name: "tagged unexported field is skipped",
+			call: func(pp *PatchPanel) error { return pp.Populate(&DebugUnexported{level: "warn"}) },
+		},
+		{
+			name:      "tagged unexported field panics in debug mode",
+			debug:     true,
+			call:      func(pp *PatchPanel) error { return pp.Populate(&DebugUnexported{}) },
+			wantPanic: "field level of string is unexported",
+		},
+		{
+			name:  "valid target in debug mode",
+			debug: true,
+			call:  func(pp *PatchPanel) error { return pp.Populate(&PopulateStruct{}) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp := New(WithDebug(tt.debug))
+			defer func() {
+				r := recover()
+				if (r != nil) != (tt.wantPanic != "") {
+					t.Fatalf("panic = %v, want panic containing %q", r, tt.wantPanic)
+				}
+				if r != nil && !strings.Contains(r.(string), tt.wantPanic) {
+					t.Errorf("panic = %v, want panic containing %q", r, tt.wantPanic)
+				}
+			}()
+			err := tt.call(pp)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.As(err, new(InvalidTargetError)) {
+				t.Errorf("error = %v, want InvalidTargetError", err)
+			}
+		})
+	}
+}
+
+func TestNew_NilOption(t *testing.T) {
+	if pp := New(nil, WithValueTag("fallback"), WithDebug(false)); pp.valueTag != "fallback" {
+		t.Errorf("New() with a nil option skipped the remaining options")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("New() with a nil option did not panic in debug mode")
+		}
+	}()
+	New(nil, WithDebug(true))
+}
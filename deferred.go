@@ -0,0 +1,52 @@
+package patchpanel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// DeferredLookup supplies the value of a late-bound field, identified by its dotted path (e.g. "Database.Host"),
+// once the dependency it waits on is available.  hints are the field's tags, so lookups may be keyed by a tag
+// such as `discover:"postgres"` rather than by path.
+type DeferredLookup func(ctx context.Context, field string, hints Hints) (string, error)
+
+// isDeferred reports whether a field is late-bound with the `deferred:"true"` tag
+func isDeferred(sF reflect.StructField) (bool, error) {
+	return Hints{"deferred": sF.Tag.Get("deferred")}.GetBool("deferred")
+}
+
+// ResolveDeferred completes the second phase of a two-phase load.  Fields tagged `deferred:"true"` are filled
+// by Populate like any other field, so their value tag (if any) serves as a placeholder; once the values they
+// depend on are available (e.g. after service discovery is up), ResolveDeferred replaces them with the result
+// of lookup.  Fields that are not deferred are left untouched.
+//
+//	type Config struct {
+//		Listen HostPort `default:":8080"`
+//		Store  HostPort `deferred:"true" discover:"store"`
+//	}
+//
+// Failures are reported as with Populate.  Resolution stops when ctx is done.
+func (pc *PatchPanel) ResolveDeferred(ctx context.Context, dst any, lookup DeferredLookup) error {
+	rv := reflect.ValueOf(dst)
+	if !rv.IsValid() || rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return pc.misuse(InvalidTargetError{Msg: fmt.Sprintf("resolve target must be a non-nil pointer to a struct, got %T", dst)})
+	}
+
+	st := pc.newPopulateState(func(sF reflect.StructField, prefix string) (string, bool, error) {
+		deferred, err := isDeferred(sF)
+		if err != nil || !deferred {
+			return "", false, err
+		}
+		if err := ctx.Err(); err != nil {
+			return "", false, err
+		}
+		raw, err := lookup(ctx, fieldPath(prefix, sF.Name), fieldHints(sF))
+		return raw, err == nil, err
+	})
+	defer st.release()
+	st.source = SourceDeferred
+	pc.populateStruct(rv.Elem(), "", st)
+	return errors.Join(st.errs...)
+}
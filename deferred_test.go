@@ -0,0 +1,95 @@
+package patchpanel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type DeferredStruct struct {
+	Listen   HostPort      `default:"localhost:8080"`
+	Store    HostPort      `default:"localhost:5432" deferred:"true" discover:"store"`
+	Cache    HostPort      `deferred:"true" discover:"cache"`
+	Timeout  time.Duration `default:"5s"`
+	Upstream struct {
+		Token string `deferred:"true" discover:"token"`
+	}
+}
+
+func TestPatchPanel_ResolveDeferred(t *testing.T) {
+	pp := New()
+
+	dst := DeferredStruct{}
+	if err := pp.Populate(&dst); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	// the first phase fills placeholders
+	if dst.Store != (HostPort{Host: "localhost", Port: 5432}) || dst.Cache != (HostPort{}) {
+		t.Fatalf("Populate() placeholders Store = %v, Cache = %v", dst.Store, dst.Cache)
+	}
+
+	discovered := map[string]string{
+		"store": "db.internal:5432",
+		"cache": "cache.internal:6379",
+		"token": "secret",
+	}
+	var paths []string
+	err := pp.ResolveDeferred(context.Background(), &dst, func(ctx context.Context, field string, hints Hints) (string, error) {
+		paths = append(paths, field)
+		name, _ := hints.GetString("discover")
+		return discovered[name], nil
+	})
+	if err != nil {
+		t.Fatalf("ResolveDeferred() error = %v", err)
+	}
+
+	if want := []string{"Store", "Cache", "Upstream.Token"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("ResolveDeferred() looked up %v, want %v", paths, want)
+	}
+	want := DeferredStruct{
+		Listen:  HostPort{Host: "localhost", Port: 8080},
+		Store:   HostPort{Host: "db.internal", Port: 5432},
+		Cache:   HostPort{Host: "cache.internal", Port: 6379},
+		Timeout: 5 * time.Second,
+	}
+	want.Upstream.Token = "secret"
+	if dst != want {
+		t.Errorf("ResolveDeferred() got = %+v, want %+v", dst, want)
+	}
+}
+
+func TestPatchPanel_ResolveDeferredErrors(t *testing.T) {
+	pp := New()
+	errUnavailable := errors.New("discovery unavailable")
+
+	dst := DeferredStruct{}
+	err := pp.ResolveDeferred(context.Background(), &dst, func(ctx context.Context, field string, hints Hints) (string, error) {
+		if field == "Cache" {
+			return "", errUnavailable
+		}
+		return "not a host port:x", nil
+	})
+	if !errors.Is(err, errUnavailable) {
+		t.Errorf("ResolveDeferred() error = %v, want lookup error", err)
+	}
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Store" {
+		t.Errorf("ResolveDeferred() error = %v, want FieldError for Store", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = pp.ResolveDeferred(ctx, &dst, func(ctx context.Context, field string, hints Hints) (string, error) {
+		t.Errorf("lookup called for %s after cancellation", field)
+		return "", nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ResolveDeferred() error = %v, want context.Canceled", err)
+	}
+
+	if err := New(WithDebug(false)).ResolveDeferred(context.Background(), dst, nil); !errors.As(err, new(InvalidTargetError)) {
+		t.Errorf("ResolveDeferred() error = %v, want InvalidTargetError", err)
+	}
+}
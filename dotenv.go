@@ -0,0 +1,204 @@
+package patchpanel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvTag names the tag holding a field's environment variable, e.g. `env:"DATABASE_URL"`
+const EnvTag = "env"
+
+// ParseDotenv parses a .env file of KEY=VALUE lines.  Blank lines and lines starting with # are skipped, and an
+// `export ` prefix is allowed so the file can also be sourced by a shell.  Values are:
+//
+//   - unquoted: trimmed, and ended by a # preceded by whitespace
+//   - 'single quoted': taken literally
+//   - "double quoted": with \n, \r, \t, \", \\ and \$ escapes, and able to span lines
+//
+// Values are not expanded; a later duplicate key replaces an earlier one.
+func ParseDotenv(data []byte) (map[string]string, error) {
+	p := dotenvParser{data: string(data), line: 1}
+	env := make(map[string]string)
+	for {
+		key, value, ok, err := p.next()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.line, err)
+		}
+		if !ok {
+			return env, nil
+		}
+		env[key] = value
+	}
+}
+
+// LoadDotenv sets the process environment from the .env file at path.  Variables already set are left alone, so
+// the real environment wins over the file, as it would in production.
+func LoadDotenv(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	env, err := ParseDotenv(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	var errs []error
+	for key, value := range env {
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			errs = append(errs, fmt.Errorf("setting %s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PopulateDotenv fills dst from a .env file without touching the process environment: each field with an EnvTag
+// reads the variable it names from data, and fields without one, or whose variable is missing, fall back to their
+// value tag.  Failures are reported as with Populate.
+func (pc *PatchPanel) PopulateDotenv(dst any, data []byte) error {
+	src, err := DotenvSource(data)
+	if err != nil {
+		return err
+	}
+	return pc.PopulateFrom(dst, src)
+}
+
+// PopulateDotenvFile is PopulateDotenv for the file at path
+func (pc *PatchPanel) PopulateDotenvFile(dst any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return pc.PopulateDotenv(dst, data)
+}
+
+// dotenvParser reads KEY=VALUE pairs from a .env file
+type dotenvParser struct {
+	data string
+	pos  int
+	line int
+}
+
+// next returns the next pair, or false at the end of the file
+func (p *dotenvParser) next() (string, string, bool, error) {
+	for {
+		if p.pos >= len(p.data) {
+			return "", "", false, nil
+		}
+		line := p.restOfLine()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' {
+			p.advance(len(line))
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return "", "", false, fmt.Errorf("expected KEY=VALUE, got %q", trimmed)
+		}
+		key := strings.TrimSpace(line[:eq])
+		key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+		if !validEnvKey(key) {
+			return "", "", false, fmt.Errorf("invalid variable name %q", key)
+		}
+		p.pos += eq + 1
+		for p.pos < len(p.data) && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+			p.pos++
+		}
+
+		value, err := p.value()
+		return key, value, err == nil, err
+	}
+}
+
+// value reads the value at pos, and the rest of its line
+func (p *dotenvParser) value() (string, error) {
+	if p.pos >= len(p.data) || (p.data[p.pos] != '"' && p.data[p.pos] != '\'') {
+		// a # starts a comment after whitespace, including the whitespace skipped after =
+		afterSpace := p.data[p.pos-1] == ' ' || p.data[p.pos-1] == '\t'
+		line := p.restOfLine()
+		p.advance(len(line))
+		for i := 0; i < len(line); i++ {
+			if line[i] == '#' && ((i == 0 && afterSpace) || (i > 0 && (line[i-1] == ' ' || line[i-1] == '\t'))) {
+				line = line[:i]
+				break
+			}
+		}
+		return strings.TrimSpace(line), nil
+	}
+
+	quote := p.data[p.pos]
+	p.pos++
+	var value strings.Builder
+	for {
+		if p.pos >= len(p.data) {
+			return "", fmt.Errorf("unterminated %c-quoted value", quote)
+		}
+		c := p.data[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			if rest := strings.TrimSpace(p.restOfLine()); rest != "" && rest[0] != '#' {
+				return "", fmt.Errorf("unexpected %q after quoted value", rest)
+			}
+			p.advance(len(p.restOfLine()))
+			return value.String(), nil
+		case c == '\n':
+			p.line++
+			value.WriteByte(c)
+		case c == '\\' && quote == '"' && p.pos < len(p.data):
+			escaped := p.data[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '"', '\\', '$':
+				value.WriteByte(escaped)
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(escaped)
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+}
+
+// restOfLine is the text from pos to the end of its line, excluding the newline
+func (p *dotenvParser) restOfLine() string {
+	line, _, _ := strings.Cut(p.data[p.pos:], "\n")
+	return strings.TrimSuffix(line, "\r")
+}
+
+// advance moves past n bytes of the current line and its newline
+func (p *dotenvParser) advance(n int) {
+	p.pos += n
+	if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		p.pos++
+		p.line++
+	}
+}
+
+// validEnvKey reports whether key is a portable environment variable name
+func validEnvKey(key string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+	for _, c := range key {
+		if c != '_' && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package patchpanel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "unquoted",
+			data: "# comment\n\nHOST=db1\nPORT = 5432 # trailing\nCOLOR=#fff\nNOTE= # only a comment\nexport MODE=dev\nEMPTY=\r\nURL=postgres://u@h/db?sslmode=disable\n",
+			want: map[string]string{"HOST": "db1", "PORT": "5432", "COLOR": "#fff", "NOTE": "", "MODE": "dev", "EMPTY": "", "URL": "postgres://u@h/db?sslmode=disable"},
+		},
+		{
+			name: "quoted",
+			data: "SINGLE='keep \\n $HOME # as is'\nDOUBLE=\"tab\\tquote\\\" \\$HOME\" # comment\nMULTI=\"line one\nline two\"\nAFTER=x\n",
+			want: map[string]string{"SINGLE": `keep \n $HOME # as is`, "DOUBLE": "tab\tquote\" $HOME", "MULTI": "line one\nline two", "AFTER": "x"},
+		},
+		{name: "duplicates", data: "A=1\nA=2", want: map[string]string{"A": "2"}},
+		{name: "missing equals", data: "A\n", wantErr: true},
+		{name: "invalid name", data: "1A=x\n", wantErr: true},
+		{name: "name with dash", data: "A-B=x\n", wantErr: true},
+		{name: "unterminated", data: "A=\"open\n", wantErr: true},
+		{name: "text after quote", data: "A='a' b\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDotenv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDotenv() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("PATCHPANEL_DOTENV_NEW=from-file\nPATCHPANEL_DOTENV_SET=from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATCHPANEL_DOTENV_SET", "from-env")
+	t.Setenv("PATCHPANEL_DOTENV_NEW", "")
+	os.Unsetenv("PATCHPANEL_DOTENV_NEW")
+
+	if err := LoadDotenv(path); err != nil {
+		t.Fatalf("LoadDotenv() error = %v", err)
+	}
+	if got := os.Getenv("PATCHPANEL_DOTENV_NEW"); got != "from-file" {
+		t.Errorf("LoadDotenv() new variable = %q", got)
+	}
+	if got := os.Getenv("PATCHPANEL_DOTENV_SET"); got != "from-env" {
+		t.Errorf("LoadDotenv() overrode variable: %q", got)
+	}
+	if err := LoadDotenv(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("LoadDotenv() of missing file succeeded")
+	}
+}
+
+type DotenvConfig struct {
+	Host     string        `env:"DB_HOST" default:"localhost"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+	Ports    []int         `env:"PORTS" sep:","`
+	Untagged string        `default:"kept"`
+	Nested   struct {
+		Level string `env:"LOG_LEVEL" default:"info"`
+	}
+}
+
+func TestPatchPanel_PopulateDotenv(t *testing.T) {
+	var conf DotenvConfig
+	err := New().PopulateDotenv(&conf, []byte("DB_HOST=db1\nPORTS=8080,8081\nLOG_LEVEL=debug\nUntagged=ignored\n"))
+	if err != nil {
+		t.Fatalf("PopulateDotenv() error = %v", err)
+	}
+	want := DotenvConfig{Host: "db1", Timeout: 5 * time.Second, Ports: []int{8080, 8081}, Untagged: "kept"}
+	want.Nested.Level = "debug"
+	if !reflect.DeepEqual(conf, want) {
+		t.Errorf("PopulateDotenv() = %+v, want %+v", conf, want)
+	}
+
+	if err := New().PopulateDotenv(&conf, []byte("TIMEOUT=soon\n")); err == nil {
+		t.Errorf("PopulateDotenv() of invalid value succeeded")
+	}
+	if err := New().PopulateDotenv(&conf, []byte("TIMEOUT\n")); err == nil {
+		t.Errorf("PopulateDotenv() of invalid file succeeded")
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("DB_HOST=from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().PopulateDotenvFile(&conf, path); err != nil || conf.Host != "from-file" {
+		t.Errorf("PopulateDotenvFile() = %v, %v", conf.Host, err)
+	}
+}
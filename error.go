@@ -1,5 +1,12 @@
 package patchpanel
 
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
 // NoFieldError allows for differentiating no named field vs parsing errors
 type NoFieldError struct {
 	Msg string
@@ -25,3 +32,125 @@ type UnhandledParserTypeError struct {
 func (u UnhandledParserTypeError) Error() string {
 	return u.Msg
 }
+
+// InvalidTargetError is returned when Populate is handed something other than a non-nil pointer to a struct
+type InvalidTargetError struct {
+	Msg string
+}
+
+func (ite InvalidTargetError) Error() string {
+	return ite.Msg
+}
+
+// LimitError is returned when populating a struct exceeds one of the panel's Limits
+type LimitError struct {
+	Msg string
+}
+
+func (le LimitError) Error() string {
+	return le.Msg
+}
+
+// ValidationError reports a parsed value that fails a constraint hint such as oneof
+type ValidationError struct {
+	Msg string
+}
+
+func (ve ValidationError) Error() string {
+	return ve.Msg
+}
+
+// RangeError reports a parsed value outside of its field's `min`, `max` or `port` hint, or a length outside of
+// its `minLen` or `maxLen` hint
+type RangeError struct {
+	Msg string
+	// Bound is the violated hint, e.g. "min" or "maxLen"
+	Bound string
+	// Limit is the hint's value, e.g. "1s"
+	Limit string
+}
+
+func (re RangeError) Error() string {
+	return re.Msg
+}
+
+// MessageError leads a parse or validation error with the message from a field's `errmsg` tag, e.g.
+// `errmsg:"PORT must be a number between 1024 and 65535"`.  The underlying error is kept for errors.As and
+// errors.Unwrap.
+type MessageError struct {
+	Msg string
+	Err error
+}
+
+func (me MessageError) Error() string {
+	return fmt.Sprintf("%s (%v)", me.Msg, me.Err)
+}
+
+func (me MessageError) Unwrap() error {
+	return me.Err
+}
+
+// LocalizedError replaces the message of a parse or validation error with one from the panel's MessageCatalog.
+// The underlying error is kept for errors.As and errors.Unwrap.
+type LocalizedError struct {
+	Msg string
+	Err error
+}
+
+func (le LocalizedError) Error() string {
+	return le.Msg
+}
+
+func (le LocalizedError) Unwrap() error {
+	return le.Err
+}
+
+// FieldError reports a failure to populate a single struct field.  The underlying error, such as
+// an UnhandledParserTypeError or a parser's own error, is available via errors.As and errors.Unwrap.
+type FieldError struct {
+	// Field is the dotted path to the field from the populated struct, e.g. "Database.Port"
+	Field string
+	// Value is the raw value that failed to coerce, or RedactedValue for a field classified as pii or secret
+	Value string
+	// Source is where Value came from: SourceTag, or SourceDeferred for ResolveDeferred
+	Source string
+	Err    error
+}
+
+func (fe FieldError) Error() string {
+	return fmt.Sprintf("field %s: value %q: %v", fe.Field, fe.Value, fe.Err)
+}
+
+func (fe FieldError) Unwrap() error {
+	return fe.Err
+}
+
+// RedactedValue stands in for the value of a field classified as DataClassPII or DataClassSecret in its
+// FieldError, and in the message of the underlying error, so that failures can be logged and reported
+const RedactedValue = "[REDACTED]"
+
+// fieldError builds the FieldError for sF, the field at path, redacting raw if the field is classified
+func (pc *PatchPanel) fieldError(sF reflect.StructField, path, raw, source string, err error) FieldError {
+	classes := pc.dataClasses(sF)
+	if slices.Contains(classes, DataClassPII) || slices.Contains(classes, DataClassSecret) {
+		if raw != "" {
+			err = redactedError{err: err, raw: raw}
+		}
+		raw = RedactedValue
+	}
+	return FieldError{Field: path, Value: raw, Source: source, Err: err}
+}
+
+// redactedError is an error whose message has a classified value redacted
+type redactedError struct {
+	err error
+	raw string
+}
+
+func (re redactedError) Error() string {
+	return strings.ReplaceAll(re.err.Error(), re.raw, RedactedValue)
+}
+
+func (re redactedError) Unwrap() error {
+	return re.err
+}
@@ -0,0 +1,92 @@
+package patchpanel
+
+import "errors"
+
+// ErrorCode is a stable, machine-readable classification of a failure in an ErrorReport
+type ErrorCode string
+
+const (
+	// CodeParseFailed is a value its parser rejected
+	CodeParseFailed ErrorCode = "PARSE_FAILED"
+	// CodeMissingRequired is a field with no value, see NoValueError
+	CodeMissingRequired ErrorCode = "MISSING_REQUIRED"
+	// CodeOutOfRange is a value outside of a min, max, port or length hint, see RangeError
+	CodeOutOfRange ErrorCode = "OUT_OF_RANGE"
+	// CodeInvalidValue is a value that fails another constraint hint, see ValidationError
+	CodeInvalidValue ErrorCode = "INVALID_VALUE"
+	// CodeUnsupportedType is a tagged field of a type with no parser, see UnhandledParserTypeError
+	CodeUnsupportedType ErrorCode = "UNSUPPORTED_TYPE"
+	// CodeLimitExceeded is a Populate stopped by the panel's Limits, see LimitError
+	CodeLimitExceeded ErrorCode = "LIMIT_EXCEEDED"
+	// CodeInvalidTarget is something other than a struct handed to Populate, see InvalidTargetError
+	CodeInvalidTarget ErrorCode = "INVALID_TARGET"
+)
+
+// ErrorReport is a serializable form of the error returned by Populate, for orchestration tooling that acts on
+// configuration failures programmatically, e.g.
+//
+//	{"errors": [{"field": "Port", "code": "OUT_OF_RANGE", "message": "port 80 is out of range 1024-65535",
+//	  "rawValue": "80", "source": "tag"}]}
+type ErrorReport struct {
+	Errors []ErrorReportEntry `json:"errors"`
+}
+
+// ErrorReportEntry is a single failure in an ErrorReport.  Field, RawValue and Source are empty for failures
+// that aren't specific to a field, such as an exceeded limit.
+type ErrorReportEntry struct {
+	Field    string    `json:"field,omitempty"`
+	Code     ErrorCode `json:"code"`
+	Message  string    `json:"message"`
+	RawValue string    `json:"rawValue,omitempty"`
+	Source   string    `json:"source,omitempty"`
+}
+
+// NewErrorReport flattens err, such as the joined FieldErrors returned by Populate, into an ErrorReport.  A nil
+// err produces an empty report.
+func NewErrorReport(err error) ErrorReport {
+	report := ErrorReport{Errors: []ErrorReportEntry{}}
+	appendReportEntries(&report, err)
+	return report
+}
+
+func appendReportEntries(report *ErrorReport, err error) {
+	if err == nil {
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			appendReportEntries(report, e)
+		}
+		return
+	}
+
+	entry := ErrorReportEntry{Code: errorCode(err), Message: err.Error()}
+	var fieldErr FieldError
+	if errors.As(err, &fieldErr) {
+		entry.Field = fieldErr.Field
+		entry.RawValue = fieldErr.Value
+		entry.Source = fieldErr.Source
+		entry.Message = fieldErr.Err.Error()
+	}
+	report.Errors = append(report.Errors, entry)
+}
+
+// errorCode classifies err by the most specific error type it wraps
+func errorCode(err error) ErrorCode {
+	switch {
+	case errors.As(err, new(LimitError)):
+		return CodeLimitExceeded
+	case errors.As(err, new(InvalidTargetError)):
+		return CodeInvalidTarget
+	case errors.As(err, new(NoValueError)):
+		return CodeMissingRequired
+	case errors.As(err, new(RangeError)):
+		return CodeOutOfRange
+	case errors.As(err, new(ValidationError)):
+		return CodeInvalidValue
+	case errors.As(err, new(UnhandledParserTypeError)):
+		return CodeUnsupportedType
+	default:
+		return CodeParseFailed
+	}
+}
@@ -0,0 +1,141 @@
+package patchpanel
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type ErrorReportStruct struct {
+	Port    int      `default:"80" port:"unprivileged"`
+	Workers int      `default:"many"`
+	Level   string   `default:"verbose" oneof:"debug·info"`
+	Channel chan int `default:"1"`
+	Name    string   `default:"app"`
+}
+
+func TestNewErrorReport(t *testing.T) {
+	err := New().Populate(&ErrorReportStruct{})
+	report := NewErrorReport(err)
+
+	want := []ErrorReportEntry{
+		{Field: "Port", Code: CodeOutOfRange, Message: "port 80 is out of range 1024-65535", RawValue: "80", Source: SourceTag},
+		{Field: "Workers", Code: CodeParseFailed, RawValue: "many", Source: SourceTag},
+		{Field: "Level", Code: CodeInvalidValue, Message: "verbose is not one of debug, info", RawValue: "verbose", Source: SourceTag},
+		{Field: "Channel", Code: CodeUnsupportedType, Message: "unknown type for parser: chan int", RawValue: "1", Source: SourceTag},
+	}
+	// parse failure messages come from strconv and aren't part of the report's contract
+	if len(report.Errors) == len(want) {
+		want[1].Message = report.Errors[1].Message
+	}
+	if !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("NewErrorReport() = %+v, want %+v", report.Errors, want)
+	}
+
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string][]map[string]string
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded["errors"][0]; got["field"] != "Port" || got["code"] != "OUT_OF_RANGE" || got["rawValue"] != "80" || got["source"] != "tag" {
+		t.Errorf("NewErrorReport() JSON entry = %v", got)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want []ErrorReportEntry
+	}{
+		{name: "nil error", err: nil, want: []ErrorReportEntry{}},
+		{
+			name: "missing value",
+			err:  NoValueError{Msg: "Port"},
+			want: []ErrorReportEntry{{Code: CodeMissingRequired, Message: "Port"}},
+		},
+		{
+			name: "limit",
+			err:  errors.Join(LimitError{Msg: "too deep"}),
+			want: []ErrorReportEntry{{Code: CodeLimitExceeded, Message: "too deep"}},
+		},
+		{
+			name: "invalid target",
+			err:  New(WithDebug(false)).Populate(ErrorReportStruct{}),
+			want: []ErrorReportEntry{{Code: CodeInvalidTarget, Message: "populate target must be a non-nil pointer to a struct, got patchpanel.ErrorReportStruct"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewErrorReport(tt.err); !reflect.DeepEqual(got.Errors, tt.want) {
+				t.Errorf("NewErrorReport() = %+v, want %+v", got.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewErrorReport_redacted(t *testing.T) {
+	type ClassifiedConfig struct {
+		PIN     int    `default:"12ab34" dataclass:"pii"`
+		Token   string `default:"hunter2" minLen:"16" dataclass:"internal · secret"`
+		Retries int    `default:"lots" dataclass:"internal"`
+	}
+	err := New().Populate(&ClassifiedConfig{})
+	report := NewErrorReport(err)
+	if len(report.Errors) != 3 {
+		t.Fatalf("NewErrorReport() = %+v, want 3 entries", report.Errors)
+	}
+	for _, entry := range report.Errors[:2] {
+		if entry.RawValue != RedactedValue || strings.Contains(entry.Message, "12ab34") || strings.Contains(entry.Message, "hunter2") {
+			t.Errorf("NewErrorReport() %s = %+v, want the value redacted", entry.Field, entry)
+		}
+	}
+	if entry := report.Errors[2]; entry.RawValue != "lots" {
+		t.Errorf("NewErrorReport() Retries = %+v, want the value kept", entry)
+	}
+	if strings.Contains(err.Error(), "12ab34") || strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("Populate() error = %v, want classified values redacted", err)
+	}
+	// the underlying error is still reachable
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Populate() error = %v, want it to wrap strconv.ErrSyntax", err)
+	}
+
+	_, err = BuildConfig(New(), SetField[ClassifiedConfig]("PIN", "56cd78"))
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Value != RedactedValue || strings.Contains(err.Error(), "56cd78") {
+		t.Errorf("BuildConfig() error = %v, want the option value redacted", err)
+	}
+}
+
+func TestWithMessageCatalog(t *testing.T) {
+	japanese := map[ErrorCode]string{
+		CodeOutOfRange:  "値が範囲外です",
+		CodeParseFailed: "値を解析できません",
+	}
+	pp := New(WithMessageCatalog(func(code ErrorCode, err error) string {
+		return japanese[code]
+	}))
+
+	err := pp.Populate(&ErrorReportStruct{})
+	report := NewErrorReport(err)
+
+	want := map[string]ErrorReportEntry{
+		"Port":    {Field: "Port", Code: CodeOutOfRange, Message: "値が範囲外です", RawValue: "80", Source: SourceTag},
+		"Workers": {Field: "Workers", Code: CodeParseFailed, Message: "値を解析できません", RawValue: "many", Source: SourceTag},
+		// codes without a translation keep their message
+		"Level": {Field: "Level", Code: CodeInvalidValue, Message: "verbose is not one of debug, info", RawValue: "verbose", Source: SourceTag},
+	}
+	for _, entry := range report.Errors {
+		if w, ok := want[entry.Field]; ok && entry != w {
+			t.Errorf("NewErrorReport() entry = %+v, want %+v", entry, w)
+		}
+	}
+	if !errors.As(err, new(RangeError)) {
+		t.Errorf("Populate() error = %v, want the localized error to wrap a RangeError", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Command server is a reference application showing patchpanel wired into an HTTP service end to end: defaults
+// from struct tags, late-bound values resolved from secrets after startup, a listener that may come from
+// systemd socket activation, and an admin handler reporting the loaded configuration.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/tristanfisher/patchpanel"
+)
+
+// Config is the server's configuration
+type Config struct {
+	Listen  patchpanel.ListenAddr `default:"tcp://127.0.0.1:8080"`
+	Admin   patchpanel.ListenAddr `default:"tcp://127.0.0.1:8081"`
+	Timeout time.Duration         `default:"30s"`
+	Drain   time.Duration         `default:"1d" durationUnits:"extended"`
+	Verbose bool                  `default:"off" boolStyle:"lenient"`
+	MaxBody patchpanel.ByteSize   `default:"1MiB"`
+
+	Upstream struct {
+		URL     string        `default:"https://api.example.com"`
+		Retries []int         `default:"100,250,1000" sep:","`
+		Backoff time.Duration `default:"250ms"`
+		// Token is read from a secret once the secret store is available
+		Token string `deferred:"true" secret:"upstream_token" json:"-"`
+	}
+}
+
+// secretLookup resolves deferred fields from systemd credentials, falling back to Docker secrets
+func secretLookup(ctx context.Context, field string, hints patchpanel.Hints) (string, error) {
+	name, err := hints.GetString("secret")
+	if err != nil || name == "" {
+		return "", fmt.Errorf("no secret named for %s", field)
+	}
+	value, err := patchpanel.SystemdCredential(name)
+	if errors.Is(err, patchpanel.ErrNoSystemdCredentials) {
+		value, err = patchpanel.DockerSecret(name)
+	}
+	return value, err
+}
+
+// loadConfig runs both phases of loading: defaults, then late-bound values
+func loadConfig(ctx context.Context, pp *patchpanel.PatchPanel, lookup patchpanel.DeferredLookup) (*Config, error) {
+	conf := &Config{}
+	if err := pp.Populate(conf); err != nil {
+		return nil, err
+	}
+	if err := pp.ResolveDeferred(ctx, conf, lookup); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// adminHandler reports the loaded configuration; secrets are excluded by their json tags
+func adminHandler(conf *Config) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(conf)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return mux
+}
+
+func appHandler(conf *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, int64(conf.MaxBody))
+		_, _ = fmt.Fprintf(w, "proxying to %s\n", conf.Upstream.URL)
+	})
+}
+
+func main() {
+	ctx := context.Background()
+	conf, err := loadConfig(ctx, patchpanel.New(), secretLookup)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	admin, err := conf.Admin.Listen()
+	if err != nil {
+		log.Fatal(err)
+	}
+	go func() {
+		log.Fatal(http.Serve(admin, adminHandler(conf)))
+	}()
+
+	listener, err := conf.Listen.Listen()
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := &http.Server{Handler: appHandler(conf), ReadTimeout: conf.Timeout, WriteTimeout: conf.Timeout}
+	log.Printf("listening on %s", conf.Listen)
+	if err := server.Serve(listener); err != nil {
+		log.Print(err)
+		os.Exit(1)
+	}
+}
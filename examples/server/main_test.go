@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tristanfisher/patchpanel"
+)
+
+func TestLoadConfig(t *testing.T) {
+	secrets := map[string]string{"upstream_token": "s3cret"}
+	lookup := func(ctx context.Context, field string, hints patchpanel.Hints) (string, error) {
+		name, _ := hints.GetString("secret")
+		return secrets[name], nil
+	}
+
+	conf, err := loadConfig(context.Background(), patchpanel.New(), lookup)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if conf.Listen.String() != "tcp://127.0.0.1:8080" || conf.Drain != 24*time.Hour || conf.Verbose {
+		t.Errorf("loadConfig() = %+v", conf)
+	}
+	if conf.Upstream.Token != "s3cret" || len(conf.Upstream.Retries) != 3 {
+		t.Errorf("loadConfig() Upstream = %+v", conf.Upstream)
+	}
+
+	server := httptest.NewServer(adminHandler(conf))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var reported map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&reported); err != nil {
+		t.Fatal(err)
+	}
+	upstream, _ := reported["Upstream"].(map[string]any)
+	if _, leaked := upstream["Token"]; leaked || upstream["URL"] != "https://api.example.com" {
+		t.Errorf("/config reported %v", reported)
+	}
+}
@@ -0,0 +1,109 @@
+package patchpanel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hints are the parser hints for a single field, keyed by hint name.  Hints usually originate from tags,
+// in which case every value is a string, but hints may also be set programmatically with typed values.
+//
+// The getters convert as needed.  A hint that is absent, nil, or an empty string (as is produced for a
+// requested tag that the field doesn't carry) is treated as not provided: Has reports false and the getters
+// return the zero value without error.
+type Hints map[string]any
+
+// Has reports whether the hint key was provided
+func (h Hints) Has(key string) bool {
+	hint, ok := h[key]
+	if !ok || hint == nil {
+		return false
+	}
+	if s, ok := hint.(string); ok && s == "" {
+		return false
+	}
+	return true
+}
+
+// GetString retrieves a string hint
+func (h Hints) GetString(key string) (string, error) {
+	if !h.Has(key) {
+		return "", nil
+	}
+	s, ok := h[key].(string)
+	if !ok {
+		return "", fmt.Errorf("%s parser hint must be a string, got %T", key, h[key])
+	}
+	return s, nil
+}
+
+// GetBool retrieves a boolean hint, e.g. `requireHost:"true"`
+func (h Hints) GetBool(key string) (bool, error) {
+	if !h.Has(key) {
+		return false, nil
+	}
+	switch hint := h[key].(type) {
+	case bool:
+		return hint, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(hint))
+		if err != nil {
+			return false, fmt.Errorf("%s parser hint must be a bool: %w", key, err)
+		}
+		return b, nil
+	}
+	return false, fmt.Errorf("%s parser hint must be a bool, got %T", key, h[key])
+}
+
+// GetInt retrieves an integer hint, e.g. `base:"16"`
+func (h Hints) GetInt(key string) (int, error) {
+	if !h.Has(key) {
+		return 0, nil
+	}
+	switch hint := h[key].(type) {
+	case int:
+		return hint, nil
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(hint))
+		if err != nil {
+			return 0, fmt.Errorf("%s parser hint must be an int: %w", key, err)
+		}
+		return i, nil
+	}
+	return 0, fmt.Errorf("%s parser hint must be an int, got %T", key, h[key])
+}
+
+// GetDuration retrieves a duration hint, e.g. `timeout:"5s"`
+func (h Hints) GetDuration(key string) (time.Duration, error) {
+	if !h.Has(key) {
+		return 0, nil
+	}
+	switch hint := h[key].(type) {
+	case time.Duration:
+		return hint, nil
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(hint))
+		if err != nil {
+			return 0, fmt.Errorf("%s parser hint must be a duration: %w", key, err)
+		}
+		return d, nil
+	}
+	return 0, fmt.Errorf("%s parser hint must be a duration, got %T", key, h[key])
+}
+
+// GetList retrieves a list hint, e.g. `schemes:"https·wss"`.  String hints are split on sep with
+// whitespace trimmed and empty entries dropped.
+func (h Hints) GetList(key string, sep string) ([]string, error) {
+	if !h.Has(key) {
+		return nil, nil
+	}
+	switch hint := h[key].(type) {
+	case []string:
+		return hint, nil
+	case string:
+		return splitTokens(hint, sep), nil
+	}
+	return nil, fmt.Errorf("%s parser hint must be a list, got %T", key, h[key])
+}
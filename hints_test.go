@@ -0,0 +1,107 @@
+package patchpanel
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHints(t *testing.T) {
+	hints := Hints{
+		"name":     "value",
+		"empty":    "",
+		"nil":      nil,
+		"flag":     "true",
+		"typed":    true,
+		"base":     "16",
+		"timeout":  "5s",
+		"schemes":  "https· wss ·",
+		"list":     []string{"a", "b"},
+		"notABool": "sometimes",
+		"number":   3,
+	}
+
+	if !hints.Has("name") || hints.Has("empty") || hints.Has("nil") || hints.Has("missing") {
+		t.Errorf("Has() reported unexpected presence")
+	}
+
+	if s, err := hints.GetString("name"); s != "value" || err != nil {
+		t.Errorf("GetString() = %q, %v", s, err)
+	}
+	if s, err := hints.GetString("missing"); s != "" || err != nil {
+		t.Errorf("GetString(missing) = %q, %v", s, err)
+	}
+	if _, err := hints.GetString("typed"); err == nil {
+		t.Errorf("GetString(typed) expected error for non-string hint")
+	}
+
+	if b, err := hints.GetBool("flag"); !b || err != nil {
+		t.Errorf("GetBool() = %v, %v", b, err)
+	}
+	if b, err := hints.GetBool("typed"); !b || err != nil {
+		t.Errorf("GetBool(typed) = %v, %v", b, err)
+	}
+	if _, err := hints.GetBool("notABool"); err == nil {
+		t.Errorf("GetBool(notABool) expected error")
+	}
+
+	if i, err := hints.GetInt("base"); i != 16 || err != nil {
+		t.Errorf("GetInt() = %v, %v", i, err)
+	}
+	if i, err := hints.GetInt("number"); i != 3 || err != nil {
+		t.Errorf("GetInt(number) = %v, %v", i, err)
+	}
+	if _, err := hints.GetInt("name"); err == nil {
+		t.Errorf("GetInt(name) expected error")
+	}
+
+	if d, err := hints.GetDuration("timeout"); d != 5*time.Second || err != nil {
+		t.Errorf("GetDuration() = %v, %v", d, err)
+	}
+	if d, err := hints.GetDuration("missing"); d != 0 || err != nil {
+		t.Errorf("GetDuration(missing) = %v, %v", d, err)
+	}
+
+	if l, err := hints.GetList("schemes", TokenSeparator); !reflect.DeepEqual(l, []string{"https", "wss"}) || err != nil {
+		t.Errorf("GetList() = %v, %v", l, err)
+	}
+	if l, err := hints.GetList("list", TokenSeparator); !reflect.DeepEqual(l, []string{"a", "b"}) || err != nil {
+		t.Errorf("GetList(list) = %v, %v", l, err)
+	}
+	if _, err := hints.GetList("number", TokenSeparator); err == nil {
+		t.Errorf("GetList(number) expected error")
+	}
+}
+
+func TestPatchPanel_AddHintsParser(t *testing.T) {
+	type Retries int
+	type Backoff int
+	type HintedConfig struct {
+		Retries Retries `default:"3" scale:"10"`
+		Backoff Backoff `default:"2" scale:"100"`
+	}
+
+	pp := New()
+	// a v1 Parser converts its hints for the typed getters
+	pp.AddParser(reflect.TypeOf(Retries(0)), func(value string, parserHints map[string]any) (any, error) {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		scale, err := Hints(parserHints).GetInt("scale")
+		return Retries(n * scale), err
+	})
+	pp.AddHintsParser(reflect.TypeOf(Backoff(0)), func(value string, parserHints Hints) (any, error) {
+		scale, err := parserHints.GetInt("scale")
+		return Backoff(len(value) * scale), err
+	})
+
+	var conf HintedConfig
+	if err := pp.Populate(&conf); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if conf != (HintedConfig{Retries: 30, Backoff: 100}) {
+		t.Errorf("Populate() = %+v", conf)
+	}
+}
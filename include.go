@@ -0,0 +1,105 @@
+package patchpanel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// IncludeKey is the top-level key of a configuration file listing other files to load beneath it, e.g.
+// `include = ["database.toml", "conf.d/*.toml"]`.  Paths may use forward slashes on every platform, and relative
+// paths are resolved against the including file's directory.  Glob patterns include every match, in lexical
+// order, and may match nothing.
+const IncludeKey = "include"
+
+// includedFile is a file on the current include chain
+type includedFile struct {
+	path string
+	info os.FileInfo
+}
+
+// loadDocument reads the configuration file at path, and the files it includes, deep-merging them into dst:
+// included files are merged in order before the including file, so its own values override theirs.  The file of
+// each value is recorded in values.  stack holds the files including this one, to detect cycles; files are
+// compared with os.SameFile, so differently cased paths on case-insensitive filesystems, and links, are still
+// recognized.  The format tag of the file is returned; included files must share it.
+func loadDocument(path string, stack []includedFile, dst map[string]any, values map[string]*MergedValue) (string, error) {
+	format, ok := documentFormats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return "", fmt.Errorf("%s: unknown configuration file format", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if slices.ContainsFunc(stack, func(f includedFile) bool { return os.SameFile(f.info, info) }) {
+		chain := make([]string, 0, len(stack)+1)
+		for _, f := range stack {
+			chain = append(chain, f.path)
+		}
+		return "", fmt.Errorf("include cycle: %s", strings.Join(append(chain, path), " -> "))
+	}
+	stack = append(stack, includedFile{path: path, info: info})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	doc, err := format.decode(data)
+	if err != nil {
+		return "", fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	includes, err := documentIncludes(doc)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	for _, include := range includes {
+		include = filepath.FromSlash(include)
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		matches := []string{include}
+		if strings.ContainsAny(include, "*?[") {
+			if matches, err = filepath.Glob(include); err != nil {
+				return "", fmt.Errorf("%s: %s pattern %q: %w", path, IncludeKey, include, err)
+			}
+		}
+		for _, match := range matches {
+			formatTag, err := loadDocument(match, stack, dst, values)
+			if err != nil {
+				return "", err
+			}
+			if formatTag != format.formatTag {
+				return "", fmt.Errorf("%s: cannot include %s from %s files", path, match, format.formatTag)
+			}
+		}
+	}
+	delete(doc, IncludeKey)
+	mergeDocument(dst, doc, "", path, values)
+	return format.formatTag, nil
+}
+
+// documentIncludes returns the paths listed under a document's IncludeKey, which holds one path or a list of them
+func documentIncludes(doc map[string]any) ([]string, error) {
+	switch include := doc[IncludeKey].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{include}, nil
+	case []any:
+		paths := make([]string, 0, len(include))
+		for _, v := range include {
+			path, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must list file paths, got %v", IncludeKey, v)
+			}
+			paths = append(paths, path)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("%s must be a file path or a list of them, got %v", IncludeKey, include)
+	}
+}
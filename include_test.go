@@ -0,0 +1,151 @@
+package patchpanel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileSource_include(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.toml": `
+include = ["db/database.toml", "workers.toml"]
+name = "billing"
+
+[database]
+port = 6432
+`,
+		"db/database.toml": `
+include = "../common.toml"
+
+[database]
+host = "db1"
+port = 5433
+`,
+		"common.toml": `
+name = "common"
+timeout = "10s"
+`,
+		"workers.toml": `
+[workers.email]
+retries = 1
+`,
+		"cycle-a.toml":    `include = "cycle-b.toml"`,
+		"cycle-b.toml":    `include = "cycle-a.toml"`,
+		"mixed.toml":      `include = "other.json"`,
+		"other.json":      `{"name": "json"}`,
+		"bad-type.toml":   `include = 3`,
+		"bad-list.toml":   `include = ["common.toml", 3]`,
+		"missing.toml":    `include = "nowhere.toml"`,
+		"self.properties": "include=self.properties",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src, err := FileSource(filepath.Join(dir, "main.toml"))
+	if err != nil {
+		t.Fatalf("FileSource() error = %v", err)
+	}
+	var conf TOMLConfig
+	if err := New().PopulateFrom(&conf, src); err != nil {
+		t.Fatalf("PopulateFrom(FileSource()) error = %v", err)
+	}
+	if conf.Name != "billing" || conf.Timeout.String() != "10s" {
+		t.Errorf("PopulateFrom(FileSource()) = %+v", conf)
+	}
+	if conf.Database.Host != "db1" || conf.Database.Port != 6432 {
+		t.Errorf("PopulateFrom(FileSource()) Database = %+v", conf.Database)
+	}
+	if conf.Workers["email"].Retries != 1 {
+		t.Errorf("PopulateFrom(FileSource()) Workers = %+v", conf.Workers)
+	}
+
+	// merge reports name the included file each value came from
+	_, report, err := MergeFiles(filepath.Join(dir, "main.toml"))
+	if err != nil {
+		t.Fatalf("MergeFiles() error = %v", err)
+	}
+	sources := make(map[string]string)
+	for _, v := range report.Values {
+		sources[v.Path] = v.File
+	}
+	if sources["timeout"] != filepath.Join(dir, "common.toml") || sources["database.host"] != filepath.Join(dir, "db", "database.toml") {
+		t.Errorf("MergeFiles() report = %s", report)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{name: "cycle-a.toml", wantErr: "include cycle"},
+		{name: "self.properties", wantErr: "include cycle"},
+		{name: "mixed.toml", wantErr: "cannot include"},
+		{name: "bad-type.toml", wantErr: "must be a file path"},
+		{name: "bad-list.toml", wantErr: "must list file paths"},
+		{name: "missing.toml", wantErr: "nowhere.toml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FileSource(filepath.Join(dir, tt.name))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("FileSource() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileSource_includePaths(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.toml":             `include = ["conf.d/*.toml", "empty.d/*.toml", "nested/dir/extra.toml"]`,
+		"conf.d/10-db.toml":     "[database]\nhost = \"db1\"\nport = 6432\n",
+		"conf.d/20-db.toml":     "[database]\nhost = \"db2\"\n",
+		"conf.d/notes.txt":      "not configuration",
+		"nested/dir/extra.toml": "name = \"extra\"\n",
+		"bad-glob.toml":         `include = "conf.d/[.toml"`,
+		"loop.toml":             `include = "alias.toml"`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// globs include their matches in order, so later files win; forward slashes work on every platform
+	src, err := FileSource(filepath.Join(dir, "main.toml"))
+	if err != nil {
+		t.Fatalf("FileSource() error = %v", err)
+	}
+	var conf TOMLConfig
+	if err := New().PopulateFrom(&conf, src); err != nil {
+		t.Fatalf("PopulateFrom(FileSource()) error = %v", err)
+	}
+	if conf.Database.Host != "db2" || conf.Database.Port != 6432 || conf.Name != "extra" {
+		t.Errorf("PopulateFrom(FileSource()) = %+v", conf)
+	}
+
+	if _, err := FileSource(filepath.Join(dir, "bad-glob.toml")); err == nil {
+		t.Errorf("FileSource() with a malformed pattern succeeded")
+	}
+
+	// a file reached through another name, such as a link, is still recognized as the same file
+	if err := os.Symlink(filepath.Join(dir, "loop.toml"), filepath.Join(dir, "alias.toml")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if _, err := FileSource(filepath.Join(dir, "loop.toml")); err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("FileSource() error = %v, want an include cycle", err)
+	}
+}
@@ -0,0 +1,295 @@
+package patchpanel
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigTag names the tag that maps a field to a key in a configuration file, ahead of any format-specific tag
+// such as json.  Keys may be dotted to reach into nested objects, e.g. `config:"database.primary.host"`.
+const ConfigTag = "config"
+
+// populateDocument fills dst from doc, a decoded configuration document of nested map[string]any objects.  Each
+// field's key is read from its ConfigTag, then from formatTag (e.g. "json"), and is otherwise the field name.
+// Values are converted to text and run through the field's parser as if they came from its value tag, which is
+// still used for keys missing from doc.
+func (pc *PatchPanel) populateDocument(dst any, doc map[string]any, formatTag string) error {
+	return pc.PopulateFrom(dst, DocumentSource(SourceFile, doc, formatTag))
+}
+
+// documentIndex maps the structs of a populated type to the document objects that hold their fields
+type documentIndex struct {
+	pc        *PatchPanel
+	formatTag string
+	// source names the document in errors
+	source string
+	// objects holds the document object of each struct, by the struct's path
+	objects map[string]map[string]any
+	// entryKeys holds the keys of each map of structs, by the map's path
+	entryKeys map[string][]string
+	// seen holds the struct types on the current path, so that self-referencing types terminate
+	seen map[reflect.Type]bool
+	// errs are failures resolving the document, such as inheritance cycles
+	errs []error
+}
+
+// add records obj as the object holding the fields of the struct type rt at prefix, descending into nested
+// structs as Populate does.  The entries of maps of structs are recorded under prefix.field.key.
+func (di *documentIndex) add(rt reflect.Type, prefix string, obj map[string]any) {
+	di.objects[prefix] = obj
+	di.seen[rt] = true
+	defer delete(di.seen, rt)
+
+	for i := 0; i < rt.NumField(); i++ {
+		sF := rt.Field(i)
+		if !sF.IsExported() && !sF.Anonymous {
+			continue
+		}
+		if _, ok := di.pc.parser(sF.Type); ok {
+			continue
+		}
+		if di.pc.isStructMap(sF.Type) {
+			value, _ := lookupDocumentKey(obj, objectKey(sF, di.formatTag))
+			entries, _ := value.(map[string]any)
+			di.addEntries(sF, fieldPath(prefix, sF.Name), entries)
+			continue
+		}
+		nested := sF.Type
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
+		}
+		if nested.Kind() != reflect.Struct || di.seen[nested] {
+			continue
+		}
+
+		// embedded structs share their parent's object, as with encoding/json
+		nestedObj := obj
+		if !sF.Anonymous || objectKey(sF, di.formatTag) != sF.Name {
+			value, _ := lookupDocumentKey(obj, objectKey(sF, di.formatTag))
+			nestedObj, _ = value.(map[string]any)
+		}
+		di.add(nested, fieldPath(prefix, sF.Name), nestedObj)
+	}
+}
+
+// InheritsKey is the document key with which an entry of a map of structs extends another entry of the same map,
+// e.g. {"canary": {"inherits": "production", "replicas": 1}}.
+const InheritsKey = "inherits"
+
+// addEntries records the entries of the map of structs sF at path.  Entries are resolved before population:
+//
+//   - an entry with an InheritsKey starts from the entry it names, recursively, and overrides it
+//   - with a `defaultKey:"default"` tag, the entry under that key seeds all others
+//
+// Missing keys then fall back to value tags as usual.
+func (di *documentIndex) addEntries(sF reflect.StructField, path string, entries map[string]any) {
+	structType := sF.Type.Elem()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if di.seen[structType] {
+		return
+	}
+
+	resolver := entryResolver{
+		entries:    entries,
+		defaultKey: sF.Tag.Get("defaultKey"),
+		resolved:   make(map[string]map[string]any, len(entries)),
+	}
+	for key := range entries {
+		entry, err := resolver.resolve(key, nil)
+		if err != nil {
+			di.errs = append(di.errs, FieldError{Field: fieldPath(path, key), Source: di.source, Err: err})
+			continue
+		}
+		di.entryKeys[path] = append(di.entryKeys[path], key)
+		di.add(structType, fieldPath(path, key), entry)
+	}
+}
+
+// entryResolver resolves the inheritance between the entries of one map of structs
+type entryResolver struct {
+	entries    map[string]any
+	defaultKey string
+	resolved   map[string]map[string]any
+}
+
+// resolve returns the entry under key merged over what it inherits.  chain holds the entries being resolved, to
+// detect cycles.
+func (er *entryResolver) resolve(key string, chain []string) (map[string]any, error) {
+	if entry, ok := er.resolved[key]; ok {
+		return entry, nil
+	}
+	if slices.Contains(chain, key) {
+		return nil, fmt.Errorf("inheritance cycle: %s -> %s", strings.Join(chain, " -> "), key)
+	}
+	value, ok := er.entries[key]
+	if !ok {
+		return nil, fmt.Errorf("%s %q: no such entry", InheritsKey, key)
+	}
+	entry, _ := value.(map[string]any)
+
+	var base map[string]any
+	switch parent, ok := entry[InheritsKey]; {
+	case ok:
+		parentKey, isString := parent.(string)
+		if !isString {
+			return nil, fmt.Errorf("%s must name an entry, got %v", InheritsKey, parent)
+		}
+		var err error
+		if base, err = er.resolve(parentKey, append(chain, key)); err != nil {
+			return nil, err
+		}
+	case key != er.defaultKey:
+		if seed, ok := er.entries[er.defaultKey].(map[string]any); ok {
+			base = seed
+		}
+	}
+
+	// merged into a copy even without a base, so the document itself is left as decoded
+	entry = mergeObjects(base, entry)
+	delete(entry, InheritsKey)
+	er.resolved[key] = entry
+	return entry, nil
+}
+
+// mergeObjects returns base overlaid with override, merging nested objects rather than replacing them
+func mergeObjects(base, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		baseObj, baseOK := merged[k].(map[string]any)
+		overrideObj, overrideOK := v.(map[string]any)
+		if baseOK && overrideOK {
+			merged[k] = mergeObjects(baseObj, overrideObj)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// SectionTag places a field in a section (or table, or object) of a configuration file, e.g. `section:"database"`.
+// On a nested struct it names the struct's section.
+const SectionTag = "section"
+
+// documentKey is the key of the value field sF in a document: its ConfigTag, else its formatTag name or field
+// name, within its SectionTag section if it has one
+func documentKey(sF reflect.StructField, formatTag string) string {
+	if key := sF.Tag.Get(ConfigTag); key != "" {
+		return key
+	}
+	key := sF.Name
+	if name := documentTagName(sF, formatTag); name != "" {
+		key = name
+	}
+	if section := sF.Tag.Get(SectionTag); section != "" {
+		return section + "." + key
+	}
+	return key
+}
+
+// objectKey is the key of the object holding the nested struct or map sF: its ConfigTag, else its SectionTag,
+// else its formatTag name or field name
+func objectKey(sF reflect.StructField, formatTag string) string {
+	if key := sF.Tag.Get(ConfigTag); key != "" {
+		return key
+	}
+	if section := sF.Tag.Get(SectionTag); section != "" {
+		return section
+	}
+	if name := documentTagName(sF, formatTag); name != "" {
+		return name
+	}
+	return sF.Name
+}
+
+// documentTagName is the name in a format tag such as `json:"name,omitempty"`, ignoring `json:"-"`.  The
+// `xml:"parent>child"` form is returned as a dotted key.
+func documentTagName(sF reflect.StructField, formatTag string) string {
+	if formatTag == "" {
+		return ""
+	}
+	name, _, _ := strings.Cut(sF.Tag.Get(formatTag), ",")
+	if name == "-" {
+		return ""
+	}
+	if formatTag == "xml" {
+		return strings.ReplaceAll(name, ">", ".")
+	}
+	return name
+}
+
+// lookupDocumentKey finds a dotted key in obj.  Each segment is matched exactly, then case-insensitively.
+func lookupDocumentKey(obj map[string]any, key string) (any, bool) {
+	var value any = obj
+	for segment := range strings.SplitSeq(key, ".") {
+		current, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = current[segment]; ok {
+			continue
+		}
+		found := false
+		for k, v := range current {
+			if strings.EqualFold(k, segment) {
+				value, found = v, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// documentValue converts a decoded document value to the text its field's parser expects.  Lists of scalars are
+// joined with sep, as a value tag would hold them; objects, and lists containing them, are re-encoded as JSON.
+func documentValue(value any, sep string) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case []any:
+		entries := make([]string, 0, len(v))
+		for _, elem := range v {
+			switch elem.(type) {
+			case map[string]any, []any:
+				return marshalDocumentValue(value)
+			}
+			entry, err := documentValue(elem, sep)
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, entry)
+		}
+		return strings.Join(entries, sep), nil
+	case map[string]any:
+		return marshalDocumentValue(value)
+	default:
+		return "", fmt.Errorf("unsupported document value of type %T", value)
+	}
+}
+
+func marshalDocumentValue(value any) (string, error) {
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
@@ -0,0 +1,447 @@
+package patchpanel
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PopulateHCL fills dst from an HCL document, as PopulateJSON does from JSON.  Attributes are looked up by their
+// field's ConfigTag, `hcl` tag name, or name; blocks populate nested structs, and labeled blocks populate maps of
+// structs, one entry per label:
+//
+//	timeout = "30s"
+//
+//	database {
+//	  host = "db1"
+//	  port = 5432
+//	}
+//
+//	worker "email" {
+//	  retries = 1
+//	}
+//
+// Expressions are limited to literal values: strings (including heredocs), numbers, bools, null, lists and objects.
+// Interpolation, references and function calls are reported as errors.
+func (pc *PatchPanel) PopulateHCL(dst any, data []byte) error {
+	doc, err := decodeHCL(string(data))
+	if err != nil {
+		return fmt.Errorf("decoding HCL config: %w", err)
+	}
+	return pc.populateDocument(dst, doc, "hcl")
+}
+
+// PopulateHCLFile is PopulateHCL for the file at path
+func (pc *PatchPanel) PopulateHCLFile(dst any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return pc.PopulateHCL(dst, data)
+}
+
+// hclDecoder decodes HCL into nested map[string]any objects, []any lists, and string, bool, int64 and float64
+// values.  A block's labels nest its body, so `worker "email" { ... }` becomes {"worker": {"email": {...}}}.
+type hclDecoder struct {
+	data string
+	pos  int
+	line int
+}
+
+func decodeHCL(data string) (map[string]any, error) {
+	d := &hclDecoder{data: data, line: 1}
+	root := make(map[string]any)
+	if err := d.body(root, false); err != nil {
+		return nil, fmt.Errorf("line %d: %w", d.line, err)
+	}
+	return root, nil
+}
+
+// body reads attributes and blocks into obj, up to the end of input or, in a block, its closing brace
+func (d *hclDecoder) body(obj map[string]any, inBlock bool) error {
+	for {
+		d.skipBlank(true)
+		if d.eof() {
+			if inBlock {
+				return fmt.Errorf("expected } to close block, found end of input")
+			}
+			return nil
+		}
+		if inBlock && d.consume("}") {
+			return nil
+		}
+
+		name, err := d.identifier()
+		if err != nil {
+			return err
+		}
+		d.skipBlank(false)
+		if d.consume("=") {
+			if err := d.attribute(obj, name); err != nil {
+				return err
+			}
+		} else if err := d.block(obj, name); err != nil {
+			return err
+		}
+
+		// an attribute or block ends its line, unless a block closes on the same line
+		d.skipBlank(false)
+		switch {
+		case d.eof():
+		case d.consume("\n"):
+			d.line++
+		case inBlock && d.peek() == '}':
+		default:
+			return fmt.Errorf("unexpected %q after %s", d.rest(10), name)
+		}
+	}
+}
+
+// attribute reads the value of the attribute name, after its =
+func (d *hclDecoder) attribute(obj map[string]any, name string) error {
+	d.skipBlank(false)
+	value, err := d.value()
+	if err != nil {
+		return err
+	}
+	if _, ok := obj[name]; ok {
+		return fmt.Errorf("%s is defined twice", name)
+	}
+	obj[name] = value
+	return nil
+}
+
+// block reads the labels and body of the block of type name
+func (d *hclDecoder) block(obj map[string]any, name string) error {
+	path := []string{name}
+	for !d.eof() && d.peek() != '{' {
+		var label string
+		var err error
+		if d.consume(`"`) {
+			label, err = d.quotedString()
+		} else {
+			label, err = d.identifier()
+		}
+		if err != nil {
+			return fmt.Errorf("block %s: %w", name, err)
+		}
+		path = append(path, label)
+		d.skipBlank(false)
+	}
+	if !d.consume("{") {
+		return fmt.Errorf("expected = or { after %s", name)
+	}
+
+	parent := obj
+	for i, segment := range path[:len(path)-1] {
+		switch next := parent[segment].(type) {
+		case nil:
+			created := make(map[string]any)
+			parent[segment] = created
+			parent = created
+		case map[string]any:
+			parent = next
+		default:
+			return fmt.Errorf("block %s conflicts with attribute %s", strings.Join(path, " "), strings.Join(path[:i+1], "."))
+		}
+	}
+	last := path[len(path)-1]
+	if _, ok := parent[last]; ok {
+		return fmt.Errorf("block %s is defined twice", strings.Join(path, " "))
+	}
+	body := make(map[string]any)
+	parent[last] = body
+	return d.body(body, true)
+}
+
+// identifier reads a name of letters, digits, underscores and dashes
+func (d *hclDecoder) identifier() (string, error) {
+	start := d.pos
+	for !d.eof() && isBareKeyChar(d.peek()) {
+		d.pos++
+	}
+	if d.pos == start {
+		if d.eof() {
+			return "", fmt.Errorf("expected a name, found end of input")
+		}
+		return "", fmt.Errorf("expected a name, found %q", d.rest(10))
+	}
+	return d.data[start:d.pos], nil
+}
+
+// value reads a literal expression
+func (d *hclDecoder) value() (any, error) {
+	if d.eof() {
+		return nil, fmt.Errorf("expected a value, found end of input")
+	}
+	switch {
+	case d.consume(`"`):
+		return d.quotedString()
+	case d.consume("<<"):
+		return d.heredoc()
+	case d.consume("["):
+		return d.list()
+	case d.consume("{"):
+		return d.object()
+	default:
+		return d.literal()
+	}
+}
+
+// quotedString reads the rest of a "string", processing escapes
+func (d *hclDecoder) quotedString() (string, error) {
+	var sb strings.Builder
+	for {
+		if d.eof() || d.peek() == '\n' {
+			return "", fmt.Errorf("unterminated string")
+		}
+		switch {
+		case d.consume(`"`):
+			return sb.String(), nil
+		case d.consume("$${"):
+			sb.WriteString("${")
+		case d.consume("%%{"):
+			sb.WriteString("%{")
+		case strings.HasPrefix(d.data[d.pos:], "${"), strings.HasPrefix(d.data[d.pos:], "%{"):
+			return "", fmt.Errorf("template sequences such as %q are not supported", d.rest(10))
+		case d.consume(`\`):
+			if err := d.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(d.data[d.pos])
+			d.pos++
+		}
+	}
+}
+
+// escape writes the character for the escape sequence following a backslash
+func (d *hclDecoder) escape(sb *strings.Builder) error {
+	if d.eof() {
+		return fmt.Errorf("unterminated escape sequence")
+	}
+	c := d.data[d.pos]
+	d.pos++
+	switch c {
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case '"', '\\':
+		sb.WriteByte(c)
+	case 'u', 'U':
+		digits := 4
+		if c == 'U' {
+			digits = 8
+		}
+		if d.pos+digits > len(d.data) {
+			return fmt.Errorf("short unicode escape")
+		}
+		code, err := strconv.ParseUint(d.data[d.pos:d.pos+digits], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return fmt.Errorf("invalid unicode escape \\%c%s", c, d.data[d.pos:d.pos+digits])
+		}
+		sb.WriteRune(rune(code))
+		d.pos += digits
+	default:
+		return fmt.Errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+// heredoc reads the rest of a <<MARKER heredoc, up to the line holding only MARKER.  The <<-MARKER form removes
+// the indentation common to its lines.
+func (d *hclDecoder) heredoc() (string, error) {
+	indented := d.consume("-")
+	marker, err := d.identifier()
+	if err != nil {
+		return "", fmt.Errorf("heredoc: %w", err)
+	}
+	d.consume("\r")
+	if !d.consume("\n") {
+		return "", fmt.Errorf("expected a newline after heredoc marker %s", marker)
+	}
+	d.line++
+
+	var lines []string
+	for {
+		if d.eof() {
+			return "", fmt.Errorf("unterminated heredoc, expected %s", marker)
+		}
+		line, _, _ := strings.Cut(d.data[d.pos:], "\n")
+		if strings.TrimSpace(line) == marker {
+			// the newline after the marker ends the attribute
+			d.pos += len(line)
+			break
+		}
+		d.pos += len(line)
+		if d.consume("\n") {
+			d.line++
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\r"))
+	}
+
+	if indented {
+		indent := -1
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+				indent = n
+			}
+		}
+		for i, line := range lines {
+			lines[i] = line[min(max(indent, 0), len(line)):]
+		}
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// list reads the rest of a [list], which may span lines
+func (d *hclDecoder) list() ([]any, error) {
+	list := []any{}
+	for {
+		d.skipBlank(true)
+		if d.consume("]") {
+			return list, nil
+		}
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+
+		d.skipBlank(true)
+		if d.consume("]") {
+			return list, nil
+		}
+		if !d.consume(",") {
+			return nil, fmt.Errorf("expected , or ] in list, found %q", d.rest(10))
+		}
+	}
+}
+
+// object reads the rest of an { object = "value" }, whose items are separated by commas or newlines
+func (d *hclDecoder) object() (map[string]any, error) {
+	obj := make(map[string]any)
+	for {
+		d.skipBlank(true)
+		if d.consume("}") {
+			return obj, nil
+		}
+		var key string
+		var err error
+		if d.consume(`"`) {
+			key, err = d.quotedString()
+		} else {
+			key, err = d.identifier()
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.skipBlank(false)
+		if !d.consume("=") && !d.consume(":") {
+			return nil, fmt.Errorf("expected = or : after object key %s", key)
+		}
+		d.skipBlank(false)
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := obj[key]; ok {
+			return nil, fmt.Errorf("object key %s is defined twice", key)
+		}
+		obj[key] = value
+
+		d.skipBlank(false)
+		if !d.consume(",") && !d.eof() && d.peek() != '\n' && d.peek() != '}' {
+			return nil, fmt.Errorf("expected , or } in object, found %q", d.rest(10))
+		}
+	}
+}
+
+// literal reads a number, bool or null
+func (d *hclDecoder) literal() (any, error) {
+	start := d.pos
+	for !d.eof() && !strings.ContainsRune(" \t\r\n,]}#/", rune(d.peek())) {
+		d.pos++
+	}
+	token := d.data[start:d.pos]
+
+	switch token {
+	case "":
+		return nil, fmt.Errorf("expected a value, found %q", d.rest(10))
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if i, err := strconv.ParseInt(token, 10, 64); err == nil {
+		return i, nil
+	}
+	if strings.ContainsAny(token, ".eE") {
+		if f, err := strconv.ParseFloat(token, 64); err == nil {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported expression %q; only literal values are supported", token)
+}
+
+// skipBlank skips spaces, tabs and comments (#, // and /* */), and newlines too when newlines is set
+func (d *hclDecoder) skipBlank(newlines bool) {
+	for !d.eof() {
+		switch c := d.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			d.pos++
+		case c == '\n' && newlines:
+			d.pos++
+			d.line++
+		case c == '#' || strings.HasPrefix(d.data[d.pos:], "//"):
+			for !d.eof() && d.peek() != '\n' {
+				d.pos++
+			}
+		case strings.HasPrefix(d.data[d.pos:], "/*"):
+			// an unterminated comment runs to the end of input
+			end := len(d.data)
+			if i := strings.Index(d.data[d.pos+2:], "*/"); i >= 0 {
+				end = d.pos + 2 + i + 2
+			}
+			d.line += strings.Count(d.data[d.pos:end], "\n")
+			d.pos = end
+		default:
+			return
+		}
+	}
+}
+
+func (d *hclDecoder) eof() bool {
+	return d.pos >= len(d.data)
+}
+
+func (d *hclDecoder) peek() byte {
+	return d.data[d.pos]
+}
+
+// consume advances past s if the input continues with it
+func (d *hclDecoder) consume(s string) bool {
+	if strings.HasPrefix(d.data[d.pos:], s) {
+		d.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// rest is up to n bytes of the remaining input, for error messages
+func (d *hclDecoder) rest(n int) string {
+	return d.data[d.pos:min(d.pos+n, len(d.data))]
+}
@@ -0,0 +1,126 @@
+package patchpanel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_decodeHCL(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "attributes",
+			data: "# comment\n// comment\n/* block\ncomment */\nname = \"app\" # trailing\nport = 8080\nratio = -1.5e2\ndebug = true\nunset = null\nescaped = \"tab\\tquote\\\" \\u00e9 $${literal}\"\n",
+			want: map[string]any{"name": "app", "port": int64(8080), "ratio": -150.0, "debug": true, "unset": nil, "escaped": "tab\tquote\" é ${literal}"},
+		},
+		{
+			name: "lists and objects",
+			data: "hosts = [\n  \"a\", # first\n  \"b\",\n]\npoint = { x = 1, \"y\": 2 }\nmulti = {\n  a = 1\n  b = [true]\n}\nempty = []\n",
+			want: map[string]any{
+				"hosts": []any{"a", "b"},
+				"point": map[string]any{"x": int64(1), "y": int64(2)},
+				"multi": map[string]any{"a": int64(1), "b": []any{true}},
+				"empty": []any{},
+			},
+		},
+		{
+			name: "heredocs",
+			data: "plain = <<EOT\nline one\n  line two\nEOT\nindented = <<-EOT\n    one\n      two\n    EOT\n",
+			want: map[string]any{"plain": "line one\n  line two\n", "indented": "one\n  two\n"},
+		},
+		{
+			name: "blocks",
+			data: "database {\n  host = \"db1\"\n  primary { port = 5432 }\n}\nworker \"email\" {\n  retries = 1\n}\nworker sms {}\nresource \"aws\" \"web\" {\n}\n",
+			want: map[string]any{
+				"database": map[string]any{"host": "db1", "primary": map[string]any{"port": int64(5432)}},
+				"worker":   map[string]any{"email": map[string]any{"retries": int64(1)}, "sms": map[string]any{}},
+				"resource": map[string]any{"aws": map[string]any{"web": map[string]any{}}},
+			},
+		},
+		{name: "duplicate attribute", data: "a = 1\na = 2\n", wantErr: true},
+		{name: "duplicate block", data: "a {}\na {}\n", wantErr: true},
+		{name: "block over attribute", data: "a = 1\na \"b\" {}\n", wantErr: true},
+		{name: "interpolation", data: "a = \"${var.x}\"\n", wantErr: true},
+		{name: "reference", data: "a = var.x\n", wantErr: true},
+		{name: "unclosed block", data: "a {\nb = 1\n", wantErr: true},
+		{name: "unterminated string", data: "a = \"open\n", wantErr: true},
+		{name: "unterminated heredoc", data: "a = <<EOT\ntext\n", wantErr: true},
+		{name: "two attributes on a line", data: "a = 1 b = 2\n", wantErr: true},
+		{name: "missing value at end", data: "a =", wantErr: true},
+		{name: "missing brace", data: "a \"b\"", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeHCL(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeHCL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeHCL() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type HCLConfig struct {
+	Name     string        `hcl:"name" default:"app"`
+	Timeout  time.Duration `hcl:"timeout" default:"5s"`
+	Ports    []int         `hcl:"ports"`
+	Database struct {
+		Host string `hcl:"host" default:"localhost"`
+		Port int    `hcl:"port" default:"5432"`
+	} `hcl:"database"`
+	Workers map[string]PopulateWorker `hcl:"worker" defaultKey:"default"`
+}
+
+func TestPatchPanel_PopulateHCL(t *testing.T) {
+	data := []byte(`
+name  = "billing"
+ports = [8080, 8081]
+
+database {
+  host = "db1"
+}
+
+worker "default" {
+  concurrency = 4
+}
+
+worker "email" {
+  retries = 1
+}
+`)
+	var conf HCLConfig
+	if err := New().PopulateHCL(&conf, data); err != nil {
+		t.Fatalf("PopulateHCL() error = %v", err)
+	}
+	if conf.Name != "billing" || conf.Timeout != 5*time.Second || !reflect.DeepEqual(conf.Ports, []int{8080, 8081}) {
+		t.Errorf("PopulateHCL() = %+v", conf)
+	}
+	if conf.Database.Host != "db1" || conf.Database.Port != 5432 {
+		t.Errorf("PopulateHCL() Database = %+v", conf.Database)
+	}
+	if email := conf.Workers["email"]; email.Concurrency != 4 || email.Retries != 1 {
+		t.Errorf("PopulateHCL() Workers = %+v", conf.Workers)
+	}
+
+	if err := New().PopulateHCL(&conf, []byte("name = \n")); err == nil {
+		t.Errorf("PopulateHCL() of invalid HCL succeeded")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.hcl")
+	if err := os.WriteFile(path, []byte(`name = "from-file"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().PopulateHCLFile(&conf, path); err != nil || conf.Name != "from-file" {
+		t.Errorf("PopulateHCLFile() = %v, %v", conf.Name, err)
+	}
+}
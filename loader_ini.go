@@ -0,0 +1,138 @@
+package patchpanel
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PopulateINI fills dst from an INI file, as PopulateJSON does from JSON.  A field's SectionTag, or the section
+// named for its nested struct, selects the section, and its ConfigTag, `ini` tag name, or name selects the key:
+//
+//	type Config struct {
+//		Host    string        `section:"database" ini:"host" default:"localhost"`
+//		Timeout time.Duration `section:"database" default:"5s"`
+//		Cache   CacheConfig   `section:"cache"`
+//	}
+//
+//	[database]
+//	host = db1
+//	Timeout = 30s
+//
+// Keys before the first section belong to the top-level struct, and dotted sections such as [database.primary]
+// reach nested structs.  Values are text: surrounding quotes are removed, and repeated `key[]` entries form lists.
+func (pc *PatchPanel) PopulateINI(dst any, data []byte) error {
+	doc, err := decodeINI(string(data))
+	if err != nil {
+		return fmt.Errorf("decoding INI config: %w", err)
+	}
+	return pc.populateDocument(dst, doc, "ini")
+}
+
+// PopulateINIFile is PopulateINI for the file at path
+func (pc *PatchPanel) PopulateINIFile(dst any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return pc.PopulateINI(dst, data)
+}
+
+// decodeINI decodes INI into nested map[string]any sections holding string and []any values.  Lines are
+// `key = value` or `key: value`, and those starting with ; or # are comments.  A later duplicate key replaces an
+// earlier one.
+func decodeINI(data string) (map[string]any, error) {
+	root := make(map[string]any)
+	section := root
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		switch {
+		case text == "" || text[0] == ';' || text[0] == '#':
+			continue
+		case text[0] == '[':
+			name, ok := strings.CutSuffix(text, "]")
+			if !ok || strings.TrimSpace(name[1:]) == "" {
+				return nil, fmt.Errorf("line %d: malformed section header %q", line, text)
+			}
+			var err error
+			if section, err = iniSection(root, iniSectionPath(name[1:])); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+
+		sep := strings.IndexAny(text, "=:")
+		if sep < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", line, text)
+		}
+		key := strings.TrimSpace(text[:sep])
+		value := iniValue(strings.TrimSpace(text[sep+1:]))
+		if key == "" {
+			return nil, fmt.Errorf("line %d: missing key", line)
+		}
+
+		if name, isList := strings.CutSuffix(key, "[]"); isList {
+			list, _ := section[name].([]any)
+			section[name] = append(list, value)
+			continue
+		}
+		if _, isSection := section[key].(map[string]any); isSection {
+			return nil, fmt.Errorf("line %d: key %q is already a section", line, key)
+		}
+		section[key] = value
+	}
+	return root, scanner.Err()
+}
+
+// iniSectionPath splits a section name into its segments: [a.b] and the git-style [a "b"] both name b within a
+func iniSectionPath(name string) []string {
+	name = strings.TrimSpace(name)
+	if head, sub, ok := strings.Cut(name, " "); ok {
+		sub = strings.TrimSpace(sub)
+		if len(sub) >= 2 && sub[0] == '"' && sub[len(sub)-1] == '"' {
+			return append(iniSectionPath(head), sub[1:len(sub)-1])
+		}
+	}
+	segments := strings.Split(name, ".")
+	for i := range segments {
+		segments[i] = strings.TrimSpace(segments[i])
+	}
+	return segments
+}
+
+// iniSection returns the section at path within root, creating it and its parents as needed
+func iniSection(root map[string]any, path []string) (map[string]any, error) {
+	section := root
+	for _, segment := range path {
+		switch existing := section[segment].(type) {
+		case map[string]any:
+			section = existing
+		case nil:
+			next := make(map[string]any)
+			section[segment] = next
+			section = next
+		default:
+			return nil, fmt.Errorf("section %q is already a key", strings.Join(path, "."))
+		}
+	}
+	return section, nil
+}
+
+// iniValue strips a value's surrounding quotes or, when it is unquoted, a trailing comment preceded by whitespace
+func iniValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	for i := 1; i < len(value); i++ {
+		if (value[i] == ';' || value[i] == '#') && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,116 @@
+package patchpanel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_decodeINI(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "keys and comments",
+			data: "; comment\n# comment\nname = app ; trailing\ncolor = #fff\nurl: http://example.com\nquoted = \" padded \"\nsingle = 'a;b'\nempty =\n",
+			want: map[string]any{"name": "app", "color": "#fff", "url": "http://example.com", "quoted": " padded ", "single": "a;b", "empty": ""},
+		},
+		{
+			name: "sections",
+			data: "top = 1\n[database]\nhost = db1\n[database.primary]\nport = 5432\n[remote \"origin\"]\nurl = git@example.com\n",
+			want: map[string]any{
+				"top":      "1",
+				"database": map[string]any{"host": "db1", "primary": map[string]any{"port": "5432"}},
+				"remote":   map[string]any{"origin": map[string]any{"url": "git@example.com"}},
+			},
+		},
+		{
+			name: "lists and duplicates",
+			data: "hosts[] = a\nhosts[] = b\nlevel = info\nlevel = debug\n",
+			want: map[string]any{"hosts": []any{"a", "b"}, "level": "debug"},
+		},
+		{name: "byte order mark", data: "\ufeffa = 1\n", want: map[string]any{"a": "1"}},
+		{name: "bare key", data: "[a]\nflag\n", wantErr: true},
+		{name: "missing key", data: "= 1\n", wantErr: true},
+		{name: "unclosed header", data: "[a\n", wantErr: true},
+		{name: "empty header", data: "[ ]\n", wantErr: true},
+		{name: "key as section", data: "a = 1\n[a]\n", wantErr: true},
+		{name: "section as key", data: "[a.b]\n[a]\nb = 1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeINI(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeINI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeINI() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type INIConfig struct {
+	Name    string        `ini:"name" default:"app"`
+	Host    string        `section:"database" ini:"host" default:"localhost"`
+	Timeout time.Duration `section:"database" default:"5s"`
+	Ports   []int         `section:"server" ini:"port"`
+	Cache   struct {
+		Size int `ini:"size" default:"64"`
+		TTL  int `ini:"ttl" default:"60"`
+	} `section:"cache"`
+	Logging struct {
+		Level string `ini:"level" default:"info"`
+	}
+}
+
+func TestPatchPanel_PopulateINI(t *testing.T) {
+	data := []byte(`
+name = billing
+
+[database]
+host = db1
+timeout = 30s
+
+[server]
+port[] = 8080
+port[] = 8081
+
+[cache]
+size = 128
+
+[logging]
+level = warn
+`)
+	var conf INIConfig
+	if err := New().PopulateINI(&conf, data); err != nil {
+		t.Fatalf("PopulateINI() error = %v", err)
+	}
+	if conf.Name != "billing" || conf.Host != "db1" || conf.Timeout != 30*time.Second {
+		t.Errorf("PopulateINI() = %+v", conf)
+	}
+	if !reflect.DeepEqual(conf.Ports, []int{8080, 8081}) {
+		t.Errorf("PopulateINI() Ports = %v", conf.Ports)
+	}
+	if conf.Cache.Size != 128 || conf.Cache.TTL != 60 || conf.Logging.Level != "warn" {
+		t.Errorf("PopulateINI() Cache = %+v, Logging = %+v", conf.Cache, conf.Logging)
+	}
+
+	if err := New().PopulateINI(&conf, []byte("[database\n")); err == nil {
+		t.Errorf("PopulateINI() of invalid INI succeeded")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(path, []byte("name = from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().PopulateINIFile(&conf, path); err != nil || conf.Name != "from-file" {
+		t.Errorf("PopulateINIFile() = %v, %v", conf.Name, err)
+	}
+}
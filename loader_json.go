@@ -0,0 +1,54 @@
+package patchpanel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// PopulateJSON fills dst from a JSON object.  Each field is looked up by its ConfigTag, its `json` tag name, or
+// its name, and its value is run through the field's parser as if it came from its value tag; fields missing from
+// the document fall back to their value tag.  Nested objects populate nested structs, allocating nil pointers to
+// them as encoding/json does, and dotted keys reach into them directly:
+//
+//	type Config struct {
+//		Timeout time.Duration `json:"timeout" default:"5s"`
+//		Host    string        `config:"database.primary.host" default:"localhost"`
+//	}
+//
+// Arrays of scalars are joined with the field's separator, so they parse as slices.  Failures are reported as
+// with Populate.
+func (pc *PatchPanel) PopulateJSON(dst any, data []byte) error {
+	doc, err := decodeJSON(data)
+	if err != nil {
+		return fmt.Errorf("decoding JSON config: %w", err)
+	}
+	return pc.populateDocument(dst, doc, "json")
+}
+
+// PopulateJSONFile is PopulateJSON for the file at path, e.g. one found with GetFileEnvOrPath
+func (pc *PatchPanel) PopulateJSONFile(dst any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return pc.PopulateJSON(dst, data)
+}
+
+// decodeJSON decodes a JSON object into nested map[string]any objects
+func decodeJSON(data []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// numbers are kept as written, so that large integers aren't rounded through float64
+	decoder.UseNumber()
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	// the document must be the only value in the file
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the top-level object at offset %d", decoder.InputOffset())
+	}
+	return doc, nil
+}
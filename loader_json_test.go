@@ -0,0 +1,209 @@
+package patchpanel
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type JSONDatabase struct {
+	Host string `json:"host" default:"localhost"`
+	Port int    `json:"port" default:"5432"`
+}
+
+type JSONEmbedded struct {
+	Region string `json:"region" default:"us-east-1"`
+}
+
+type JSONConfig struct {
+	JSONEmbedded
+	Name     string          `json:"name,omitempty" default:"app"`
+	Timeout  time.Duration   `json:"timeout" default:"5s"`
+	Debug    bool            `json:"debug"`
+	Big      uint64          `json:"big"`
+	Hosts    []string        `json:"hosts"`
+	Ports    []int           `json:"ports" sep:","`
+	Primary  JSONDatabase    `json:"primary"`
+	Replica  *JSONDatabase   `json:"replica"`
+	Shard    string          `config:"primary.shard" default:"a"`
+	Untagged string          `default:"none"`
+	Extra    json.RawMessage `json:"extra"`
+	Skipped  string          `json:"-" default:"skipped"`
+}
+
+func TestPatchPanel_PopulateJSON(t *testing.T) {
+	data := []byte(`{
+		"region": "eu-west-1",
+		"timeout": "30s",
+		"debug": true,
+		"big": 18446744073709551615,
+		"hosts": ["a.example.com", "b.example.com"],
+		"ports": [8080, 8081],
+		"primary": {"host": "db1", "shard": "c"},
+		"replica": {"port": 5433},
+		"UNTAGGED": "case-insensitive",
+		"extra": {"nested": [1, 2]},
+		"-": "ignored"
+	}`)
+
+	conf := JSONConfig{Replica: &JSONDatabase{}}
+	if err := New().PopulateJSON(&conf, data); err != nil {
+		t.Fatalf("PopulateJSON() error = %v", err)
+	}
+	want := JSONConfig{
+		JSONEmbedded: JSONEmbedded{Region: "eu-west-1"},
+		Name:         "app",
+		Timeout:      30 * time.Second,
+		Debug:        true,
+		Big:          18446744073709551615,
+		Hosts:        []string{"a.example.com", "b.example.com"},
+		Ports:        []int{8080, 8081},
+		Primary:      JSONDatabase{Host: "db1", Port: 5432},
+		Replica:      &JSONDatabase{Host: "localhost", Port: 5433},
+		Shard:        "c",
+		Untagged:     "case-insensitive",
+		Extra:        json.RawMessage(`{"nested":[1,2]}`),
+		Skipped:      "skipped",
+	}
+	if !reflect.DeepEqual(conf, want) {
+		t.Errorf("PopulateJSON() = %+v, want %+v", conf, want)
+	}
+}
+
+func TestPatchPanel_PopulateJSONNilPointer(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want *JSONDatabase
+	}{
+		{name: "object", data: `{"replica": {"host": "db2"}}`, want: &JSONDatabase{Host: "db2", Port: 5432}},
+		{name: "empty object", data: `{"replica": {}}`, want: &JSONDatabase{Host: "localhost", Port: 5432}},
+		{name: "null", data: `{"replica": null}`},
+		{name: "missing", data: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conf JSONConfig
+			if err := New().PopulateJSON(&conf, []byte(tt.data)); err != nil {
+				t.Fatalf("PopulateJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(conf.Replica, tt.want) {
+				t.Errorf("PopulateJSON() Replica = %+v, want %+v", conf.Replica, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchPanel_PopulateJSONErrors(t *testing.T) {
+	pp := New()
+
+	var conf JSONConfig
+	err := pp.PopulateJSON(&conf, []byte(`{"timeout": "soon", "primary": {"port": "five"}}`))
+	var fieldErrs []FieldError
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fieldErr FieldError
+		if errors.As(e, &fieldErr) {
+			fieldErrs = append(fieldErrs, fieldErr)
+		}
+	}
+	if len(fieldErrs) != 2 || fieldErrs[0].Field != "Timeout" || fieldErrs[1].Field != "Primary.Port" {
+		t.Fatalf("PopulateJSON() error = %v, want failures for Timeout and Primary.Port", err)
+	}
+	if fieldErrs[0].Source != SourceFile || fieldErrs[0].Value != "soon" {
+		t.Errorf("PopulateJSON() Timeout error = %+v, want the file value", fieldErrs[0])
+	}
+
+	for _, data := range []string{`[1, 2]`, `{"name": "a"} garbage`, `{"name": "a"} {"name": "b"}`, `{"name": "a"}]`} {
+		if err := pp.PopulateJSON(&conf, []byte(data)); err == nil {
+			t.Errorf("PopulateJSON(%s) succeeded", data)
+		}
+	}
+	if err := New(WithDebug(false)).PopulateJSON(conf, []byte(`{}`)); !errors.As(err, new(InvalidTargetError)) {
+		t.Errorf("PopulateJSON() error = %v, want InvalidTargetError", err)
+	}
+}
+
+func TestPatchPanel_PopulateJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"name": "billing"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var conf JSONConfig
+	if err := New().PopulateJSONFile(&conf, path); err != nil {
+		t.Fatalf("PopulateJSONFile() error = %v", err)
+	}
+	if conf.Name != "billing" || conf.Timeout != 5*time.Second {
+		t.Errorf("PopulateJSONFile() = %+v", conf)
+	}
+	if err := New().PopulateJSONFile(&conf, path+".missing"); err == nil {
+		t.Errorf("PopulateJSONFile() of a missing file succeeded")
+	}
+}
+
+func TestPatchPanel_PopulateJSONInherits(t *testing.T) {
+	pp := New()
+
+	var conf PopulateQueues
+	err := pp.PopulateJSON(&conf, []byte(`{
+		"workers": {
+			"default": {"timeout": "1m"},
+			"production": {"concurrency": 16, "retries": 5},
+			"canary": {"inherits": "production", "concurrency": 2},
+			"canary-eu": {"inherits": "canary", "retries": 0}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("PopulateJSON() error = %v", err)
+	}
+	want := map[string]PopulateWorker{
+		"default":    {Concurrency: 1, Retries: 3, Timeout: time.Minute},
+		"production": {Concurrency: 16, Retries: 5, Timeout: time.Minute},
+		"canary":     {Concurrency: 2, Retries: 5, Timeout: time.Minute},
+		"canary-eu":  {Concurrency: 2, Retries: 0, Timeout: time.Minute},
+	}
+	if !reflect.DeepEqual(conf.Workers, want) {
+		t.Errorf("PopulateJSON() Workers = %+v, want %+v", conf.Workers, want)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "cycle",
+			data:    `{"regions": {"a": {"inherits": "b"}, "b": {"inherits": "a"}, "c": {}}}`,
+			wantErr: "inheritance cycle",
+		},
+		{
+			name:    "unknown entry",
+			data:    `{"regions": {"a": {"inherits": "missing"}}}`,
+			wantErr: `inherits "missing": no such entry`,
+		},
+		{
+			name:    "not a name",
+			data:    `{"regions": {"a": {"inherits": 1}}}`,
+			wantErr: "inherits must name an entry",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conf PopulateQueues
+			err := pp.PopulateJSON(&conf, []byte(tt.data))
+			var fieldErr FieldError
+			if !errors.As(err, &fieldErr) || !strings.Contains(fieldErr.Err.Error(), tt.wantErr) {
+				t.Errorf("PopulateJSON() error = %v, want %q", err, tt.wantErr)
+			}
+			// entries that resolve are still populated
+			if tt.name == "cycle" && conf.Regions["c"] == nil {
+				t.Errorf("PopulateJSON() skipped a valid entry: %+v", conf.Regions)
+			}
+		})
+	}
+}
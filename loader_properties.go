@@ -0,0 +1,168 @@
+package patchpanel
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PopulateProperties fills dst from a Java .properties file, as PopulateJSON does from JSON.  Dotted keys are
+// split into nested objects, so `db.host=db1` populates the Host field of a nested DB struct, or a field tagged
+// `config:"db.host"`; fields are also looked up by their `properties` tag name:
+//
+//	db.host=db1
+//	db.port: 5432
+//	db.pool.size 10
+//
+// A key may hold both a value and nested keys, as in `log=info` and `log.level=debug`: the value is kept under
+// PropertiesValueKey within the nested object, and is still what a field keyed `log` reads.
+func (pc *PatchPanel) PopulateProperties(dst any, data []byte) error {
+	doc, err := decodeProperties(string(data))
+	if err != nil {
+		return fmt.Errorf("decoding properties config: %w", err)
+	}
+	return pc.populateDocument(dst, doc, "properties")
+}
+
+// PopulatePropertiesFile is PopulateProperties for the file at path
+func (pc *PatchPanel) PopulatePropertiesFile(dst any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return pc.PopulateProperties(dst, data)
+}
+
+// PropertiesValueKey is the key under which the value of a properties key that also has nested keys is kept, e.g.
+// `config:"log.#value"` for log=info alongside log.level=debug
+const PropertiesValueKey = "#value"
+
+// decodeProperties decodes a .properties file into nested map[string]any objects of string values, following
+// java.util.Properties: keys and values are separated by =, : or whitespace, lines starting with # or ! are
+// comments, a line ending in an unescaped backslash continues on the next, and a later duplicate key replaces an
+// earlier one.
+func decodeProperties(data string) (map[string]any, error) {
+	root := make(map[string]any)
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// join continuation lines, dropping each one's leading whitespace
+		for continuesLine(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if continuesLine(line) {
+			// a continuation at the end of the file continues onto nothing
+			line = line[:len(line)-1]
+		}
+
+		rawKey, rawValue := splitProperty(line)
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		value, err := unescapeProperty(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		setProperty(root, key, value)
+	}
+	return root, nil
+}
+
+// continuesLine reports whether line ends in an odd number of backslashes, continuing on the next line
+func continuesLine(line string) bool {
+	trailing := len(line) - len(strings.TrimRight(line, `\`))
+	return trailing%2 == 1
+}
+
+// splitProperty splits a line at the first unescaped =, : or whitespace.  Whitespace around the separator is
+// dropped, as is a single = or : following whitespace.
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			value := strings.TrimLeft(line[i:], " \t\f")
+			if value != "" && (value[0] == '=' || value[0] == ':') {
+				value = strings.TrimLeft(value[1:], " \t\f")
+			}
+			return line[:i], value
+		}
+	}
+	return line, ""
+}
+
+// unescapeProperty processes the escapes of a key or value: \t, \n, \r, \f, \uXXXX, and a backslash before any
+// other character stands for that character
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("short unicode escape")
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape \\u%s", s[i+1:i+5])
+			}
+			sb.WriteRune(rune(code))
+			i += 4
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), nil
+}
+
+// setProperty sets the dotted key below root, creating nested objects.  A key with both a value and nested keys
+// keeps the value under PropertiesValueKey in its object.
+func setProperty(root map[string]any, key, value string) {
+	segments := strings.Split(key, ".")
+	obj := root
+	for _, segment := range segments[:len(segments)-1] {
+		switch next := obj[segment].(type) {
+		case map[string]any:
+			obj = next
+		case string:
+			created := map[string]any{PropertiesValueKey: next}
+			obj[segment] = created
+			obj = created
+		default:
+			created := make(map[string]any)
+			obj[segment] = created
+			obj = created
+		}
+	}
+	name := segments[len(segments)-1]
+	if nested, isObject := obj[name].(map[string]any); isObject {
+		nested[PropertiesValueKey] = value
+		return
+	}
+	obj[name] = value
+}
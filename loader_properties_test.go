@@ -0,0 +1,110 @@
+package patchpanel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_decodeProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "separators and comments",
+			data: "# comment\n! comment\nname=app\nport: 8080\n  mode   debug\nspaced = a b \nempty\nlast=1\nlast=2\r\n",
+			want: map[string]any{"name": "app", "port": "8080", "mode": "debug", "spaced": "a b ", "empty": "", "last": "2"},
+		},
+		{
+			name: "dotted keys",
+			data: "db.host=db1\ndb.port=5432\ndb.pool.size=10\n",
+			want: map[string]any{"db": map[string]any{"host": "db1", "port": "5432", "pool": map[string]any{"size": "10"}}},
+		},
+		{
+			name: "escapes and continuations",
+			data: "key\\ with\\=sep=tab\\there \\u00e9\npath=C:\\\\dir\\\\\nlist=a,\\\n     b,\\\n     c\nend=x\\",
+			want: map[string]any{"key with=sep": "tab\there é", "path": `C:\dir\`, "list": "a,b,c", "end": "x"},
+		},
+		{
+			name: "value then nested",
+			data: "log=info\nlog.level=debug\nlog.level.console=warn\n",
+			want: map[string]any{"log": map[string]any{"#value": "info", "level": map[string]any{"#value": "debug", "console": "warn"}}},
+		},
+		{
+			name: "nested then value",
+			data: "log.level=debug\nlog=info\nlog=error\n",
+			want: map[string]any{"log": map[string]any{"#value": "error", "level": "debug"}},
+		},
+		{name: "short unicode escape", data: "a=\\u00\n", wantErr: true},
+		{name: "invalid unicode escape", data: "a=\\uzzzz\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeProperties(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeProperties() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeProperties() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type PropertiesConfig struct {
+	Name    string        `properties:"app.name" default:"app"`
+	Timeout time.Duration `config:"app.timeout" default:"5s"`
+	DB      struct {
+		Host string `properties:"host" default:"localhost"`
+		Port int    `properties:"port" default:"5432"`
+		Pool struct {
+			Size int `properties:"size" default:"4"`
+		} `properties:"pool"`
+	} `properties:"db"`
+}
+
+func TestPatchPanel_PopulateProperties(t *testing.T) {
+	data := []byte(`
+app.name=billing
+app.timeout=30s
+db.host=db1
+db.pool.size=10
+`)
+	var conf PropertiesConfig
+	if err := New().PopulateProperties(&conf, data); err != nil {
+		t.Fatalf("PopulateProperties() error = %v", err)
+	}
+	if conf.Name != "billing" || conf.Timeout != 30*time.Second {
+		t.Errorf("PopulateProperties() = %+v", conf)
+	}
+	if conf.DB.Host != "db1" || conf.DB.Port != 5432 || conf.DB.Pool.Size != 10 {
+		t.Errorf("PopulateProperties() DB = %+v", conf.DB)
+	}
+
+	// a key with both a value and nested keys keeps both addressable
+	var logging struct {
+		Log      string `properties:"log"`
+		LogLevel string `config:"log.level"`
+		Raw      string `config:"log.#value"`
+	}
+	if err := New().PopulateProperties(&logging, []byte("log=stdout\nlog.level=debug\n")); err != nil {
+		t.Fatalf("PopulateProperties() error = %v", err)
+	}
+	if logging.Log != "stdout" || logging.LogLevel != "debug" || logging.Raw != "stdout" {
+		t.Errorf("PopulateProperties() = %+v", logging)
+	}
+
+	path := filepath.Join(t.TempDir(), "application.properties")
+	if err := os.WriteFile(path, []byte("app.name=from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().PopulatePropertiesFile(&conf, path); err != nil || conf.Name != "from-file" {
+		t.Errorf("PopulatePropertiesFile() = %v, %v", conf.Name, err)
+	}
+}
@@ -0,0 +1,594 @@
+package patchpanel
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// PopulateTOML fills dst from a TOML document, as PopulateJSON does from JSON.  Fields are looked up by their
+// ConfigTag, their `toml` tag name, or their name; tables populate nested structs, and arrays of scalars populate
+// slices:
+//
+//	timeout = "30s"
+//
+//	[database.primary]
+//	host = "db1"
+//	port = 5432
+//
+// Offset date-times are passed to parsers in RFC 3339, and local dates and times as written.
+func (pc *PatchPanel) PopulateTOML(dst any, data []byte) error {
+	doc, err := decodeTOML(string(data))
+	if err != nil {
+		return fmt.Errorf("decoding TOML config: %w", err)
+	}
+	return pc.populateDocument(dst, doc, "toml")
+}
+
+// PopulateTOMLFile is PopulateTOML for the file at path
+func (pc *PatchPanel) PopulateTOMLFile(dst any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return pc.PopulateTOML(dst, data)
+}
+
+// tomlDecoder decodes TOML into nested map[string]any tables, []any arrays, and string, bool, int64, float64 and
+// time.Time values
+type tomlDecoder struct {
+	data string
+	pos  int
+	line int
+	root map[string]any
+	// table is the table key/value pairs are currently added to
+	table map[string]any
+	// headers holds the tables defined by a [table] header, which may not be defined again
+	headers map[uintptr]bool
+	// arrays holds the keys of arrays of tables, as opposed to arrays of values
+	arrays map[tomlKey]bool
+}
+
+// tomlKey identifies a key of a table
+type tomlKey struct {
+	table uintptr
+	key   string
+}
+
+// tableID identifies a decoded table; tables are never copied, so their address is stable
+func tableID(table map[string]any) uintptr {
+	return reflect.ValueOf(table).Pointer()
+}
+
+func decodeTOML(data string) (map[string]any, error) {
+	d := &tomlDecoder{data: data, line: 1, root: make(map[string]any), headers: make(map[uintptr]bool), arrays: make(map[tomlKey]bool)}
+	d.table = d.root
+	if err := d.decode(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", d.line, err)
+	}
+	return d.root, nil
+}
+
+func (d *tomlDecoder) decode() error {
+	for {
+		d.skipBlank(true)
+		if d.eof() {
+			return nil
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(d.data[d.pos:], "[["):
+			d.pos += 2
+			err = d.arrayTableHeader()
+		case d.peek() == '[':
+			d.pos++
+			err = d.tableHeader()
+		default:
+			err = d.keyValue(d.table)
+		}
+		if err != nil {
+			return err
+		}
+		if err := d.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+// tableHeader reads the rest of a [table] header
+func (d *tomlDecoder) tableHeader() error {
+	path, err := d.key()
+	if err != nil {
+		return err
+	}
+	if !d.consume("]") {
+		return fmt.Errorf("expected ] to close table header")
+	}
+	parent, err := d.descend(d.root, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	last := path[len(path)-1]
+	var table map[string]any
+	switch existing := parent[last].(type) {
+	case nil:
+		table = make(map[string]any)
+		parent[last] = table
+	case map[string]any:
+		// a table created implicitly, e.g. by [a.b], may be defined once by its own header
+		if d.headers[tableID(existing)] {
+			return fmt.Errorf("table %s is defined twice", strings.Join(path, "."))
+		}
+		table = existing
+	default:
+		return fmt.Errorf("cannot define table under non-table key %s", strings.Join(path, "."))
+	}
+	d.headers[tableID(table)] = true
+	d.table = table
+	return nil
+}
+
+// arrayTableHeader reads the rest of an [[array.of.tables]] header, appending a new table to the array
+func (d *tomlDecoder) arrayTableHeader() error {
+	path, err := d.key()
+	if err != nil {
+		return err
+	}
+	if !d.consume("]]") {
+		return fmt.Errorf("expected ]] to close array of tables header")
+	}
+	parent, err := d.descend(d.root, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	last := path[len(path)-1]
+	var array []any
+	switch existing := parent[last].(type) {
+	case nil:
+	case []any:
+		if !d.arrays[tomlKey{tableID(parent), last}] {
+			return fmt.Errorf("cannot define table under non-table key %s", strings.Join(path, "."))
+		}
+		array = existing
+	default:
+		return fmt.Errorf("key %s is already defined as %T", strings.Join(path, "."), existing)
+	}
+	table := make(map[string]any)
+	parent[last] = append(array, table)
+	d.arrays[tomlKey{tableID(parent), last}] = true
+	d.table = table
+	return nil
+}
+
+// descend returns the table at path below table, creating missing tables.  An array of tables resolves to its
+// last table, as in TOML's [[fruit]] / [fruit.variety] form.
+func (d *tomlDecoder) descend(table map[string]any, path []string) (map[string]any, error) {
+	for i, segment := range path {
+		switch next := table[segment].(type) {
+		case nil:
+			created := make(map[string]any)
+			table[segment] = created
+			table = created
+		case map[string]any:
+			table = next
+		case []any:
+			// only an array of tables, which is never empty, resolves to its last table
+			if len(next) == 0 || !d.arrays[tomlKey{tableID(table), segment}] {
+				return nil, fmt.Errorf("cannot define table under non-table key %s", strings.Join(path[:i+1], "."))
+			}
+			table = next[len(next)-1].(map[string]any)
+		default:
+			return nil, fmt.Errorf("key %s is already defined as a value", strings.Join(path[:i+1], "."))
+		}
+	}
+	return table, nil
+}
+
+// keyValue reads a `key = value` pair into table; dotted keys create nested tables
+func (d *tomlDecoder) keyValue(table map[string]any) error {
+	path, err := d.key()
+	if err != nil {
+		return err
+	}
+	d.skipBlank(false)
+	if !d.consume("=") {
+		return fmt.Errorf("expected = after key %s", strings.Join(path, "."))
+	}
+	d.skipBlank(false)
+	value, err := d.value()
+	if err != nil {
+		return err
+	}
+
+	parent, err := d.descend(table, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	last := path[len(path)-1]
+	if _, ok := parent[last]; ok {
+		return fmt.Errorf("key %s is defined twice", strings.Join(path, "."))
+	}
+	parent[last] = value
+	return nil
+}
+
+// key reads a possibly dotted, possibly quoted key
+func (d *tomlDecoder) key() ([]string, error) {
+	var path []string
+	for {
+		d.skipBlank(false)
+		if d.eof() {
+			return nil, fmt.Errorf("expected a key, found end of input")
+		}
+		var segment string
+		var err error
+		switch d.peek() {
+		case '"':
+			d.pos++
+			segment, err = d.basicString()
+		case '\'':
+			d.pos++
+			segment, err = d.literalString()
+		default:
+			start := d.pos
+			for !d.eof() && isBareKeyChar(d.peek()) {
+				d.pos++
+			}
+			if start == d.pos {
+				return nil, fmt.Errorf("expected a key, found %q", d.rest(10))
+			}
+			segment = d.data[start:d.pos]
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, segment)
+
+		d.skipBlank(false)
+		if !d.consume(".") {
+			return path, nil
+		}
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// value reads any TOML value
+func (d *tomlDecoder) value() (any, error) {
+	if d.eof() {
+		return nil, fmt.Errorf("expected a value, found end of input")
+	}
+	switch {
+	case strings.HasPrefix(d.data[d.pos:], `"""`):
+		d.pos += 3
+		return d.multilineString('"')
+	case strings.HasPrefix(d.data[d.pos:], "'''"):
+		d.pos += 3
+		return d.multilineString('\'')
+	case d.peek() == '"':
+		d.pos++
+		return d.basicString()
+	case d.peek() == '\'':
+		d.pos++
+		return d.literalString()
+	case d.peek() == '[':
+		d.pos++
+		return d.array()
+	case d.peek() == '{':
+		d.pos++
+		return d.inlineTable()
+	default:
+		return d.scalar()
+	}
+}
+
+// basicString reads the rest of a "string", processing escapes
+func (d *tomlDecoder) basicString() (string, error) {
+	var sb strings.Builder
+	for {
+		if d.eof() || d.peek() == '\n' {
+			return "", fmt.Errorf("unterminated string")
+		}
+		c := d.data[d.pos]
+		d.pos++
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if err := d.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// literalString reads the rest of a 'string', which has no escapes
+func (d *tomlDecoder) literalString() (string, error) {
+	end := strings.IndexAny(d.data[d.pos:], "'\n")
+	if end < 0 || d.data[d.pos+end] != '\'' {
+		return "", fmt.Errorf("unterminated string")
+	}
+	s := d.data[d.pos : d.pos+end]
+	d.pos += end + 1
+	return s, nil
+}
+
+// multilineString reads the rest of a """string""" or ”'string”' delimited by quote.  A newline directly after
+// the opening delimiter is trimmed, and in basic strings a backslash at the end of a line trims the following
+// whitespace.
+func (d *tomlDecoder) multilineString(quote byte) (string, error) {
+	delimiter := strings.Repeat(string(quote), 3)
+	if strings.HasPrefix(d.data[d.pos:], "\r\n") {
+		d.pos += 2
+		d.line++
+	} else if d.consume("\n") {
+		d.line++
+	}
+
+	var sb strings.Builder
+	for {
+		if d.eof() {
+			return "", fmt.Errorf("unterminated multi-line string")
+		}
+		if strings.HasPrefix(d.data[d.pos:], delimiter) {
+			d.pos += 3
+			// up to two quotes may directly precede the closing delimiter
+			for i := 0; i < 2 && !d.eof() && d.peek() == quote; i++ {
+				sb.WriteByte(quote)
+				d.pos++
+			}
+			return sb.String(), nil
+		}
+
+		c := d.data[d.pos]
+		d.pos++
+		switch {
+		case c == '\n':
+			d.line++
+			sb.WriteByte(c)
+		case c == '\\' && quote == '"':
+			if rest := strings.TrimLeft(d.data[d.pos:], " \t\r"); strings.HasPrefix(rest, "\n") {
+				// line ending backslash
+				for !d.eof() && strings.ContainsRune(" \t\r\n", rune(d.peek())) {
+					if d.peek() == '\n' {
+						d.line++
+					}
+					d.pos++
+				}
+				continue
+			}
+			if err := d.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// escape writes the character for the escape sequence following a backslash
+func (d *tomlDecoder) escape(sb *strings.Builder) error {
+	if d.eof() {
+		return fmt.Errorf("unterminated escape sequence")
+	}
+	c := d.data[d.pos]
+	d.pos++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'e':
+		sb.WriteByte(0x1b)
+	case '"', '\\':
+		sb.WriteByte(c)
+	case 'u', 'U':
+		digits := 4
+		if c == 'U' {
+			digits = 8
+		}
+		if d.pos+digits > len(d.data) {
+			return fmt.Errorf("short unicode escape")
+		}
+		code, err := strconv.ParseUint(d.data[d.pos:d.pos+digits], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return fmt.Errorf("invalid unicode escape \\%c%s", c, d.data[d.pos:d.pos+digits])
+		}
+		sb.WriteRune(rune(code))
+		d.pos += digits
+	default:
+		return fmt.Errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+// array reads the rest of an [array], which may span lines
+func (d *tomlDecoder) array() ([]any, error) {
+	array := []any{}
+	for {
+		d.skipBlank(true)
+		if d.consume("]") {
+			return array, nil
+		}
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+
+		d.skipBlank(true)
+		if d.consume("]") {
+			return array, nil
+		}
+		if !d.consume(",") {
+			return nil, fmt.Errorf("expected , or ] in array, found %q", d.rest(10))
+		}
+	}
+}
+
+// inlineTable reads the rest of an {inline = "table"}
+func (d *tomlDecoder) inlineTable() (map[string]any, error) {
+	table := make(map[string]any)
+	d.skipBlank(false)
+	if d.consume("}") {
+		return table, nil
+	}
+	for {
+		if err := d.keyValue(table); err != nil {
+			return nil, err
+		}
+		d.skipBlank(false)
+		if d.consume("}") {
+			return table, nil
+		}
+		if !d.consume(",") {
+			return nil, fmt.Errorf("expected , or } in inline table, found %q", d.rest(10))
+		}
+	}
+}
+
+// scalar reads a boolean, number, or date/time
+func (d *tomlDecoder) scalar() (any, error) {
+	start := d.pos
+	for !d.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(d.peek())) {
+		d.pos++
+	}
+	// a date and time may be separated by a space, e.g. 1979-05-27 07:32:00Z
+	if d.pos-start == 10 && d.data[start+4] == '-' && d.pos+1 < len(d.data) && d.peek() == ' ' && isDigit(d.data[d.pos+1]) {
+		d.pos++
+		for !d.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(d.peek())) {
+			d.pos++
+		}
+	}
+	token := d.data[start:d.pos]
+
+	switch token {
+	case "":
+		return nil, fmt.Errorf("expected a value, found %q", d.rest(10))
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+
+	// dates and times
+	if len(token) >= 8 && (token[2] == ':' || len(token) >= 10 && token[4] == '-') {
+		if t, err := time.Parse(time.RFC3339Nano, strings.Replace(token, " ", "T", 1)); err == nil {
+			return t, nil
+		}
+		// local date-times, dates and times have no single instant; their parsers take them as written
+		return token, nil
+	}
+
+	digits := strings.ReplaceAll(token, "_", "")
+	if len(digits) > 2 && digits[0] == '0' {
+		base := 0
+		switch digits[1] {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+		if base != 0 {
+			i, err := strconv.ParseInt(digits[2:], base, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer %q", token)
+			}
+			return i, nil
+		}
+	}
+	if strings.ContainsAny(digits, ".eE") {
+		f, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %q", token)
+		}
+		return f, nil
+	}
+	i, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q", token)
+	}
+	return i, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// skipBlank skips spaces, tabs and comments, and newlines too when newlines is set
+func (d *tomlDecoder) skipBlank(newlines bool) {
+	for !d.eof() {
+		switch c := d.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			d.pos++
+		case c == '\n' && newlines:
+			d.pos++
+			d.line++
+		case c == '#':
+			for !d.eof() && d.peek() != '\n' {
+				d.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine requires that nothing but a comment follows on the current line
+func (d *tomlDecoder) endOfLine() error {
+	d.skipBlank(false)
+	if d.eof() {
+		return nil
+	}
+	if !d.consume("\n") {
+		return fmt.Errorf("unexpected %q after value", d.rest(10))
+	}
+	d.line++
+	return nil
+}
+
+func (d *tomlDecoder) eof() bool {
+	return d.pos >= len(d.data)
+}
+
+func (d *tomlDecoder) peek() byte {
+	return d.data[d.pos]
+}
+
+// consume advances past s if the input continues with it
+func (d *tomlDecoder) consume(s string) bool {
+	if strings.HasPrefix(d.data[d.pos:], s) {
+		d.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// rest is up to n bytes of the remaining input, for error messages
+func (d *tomlDecoder) rest(n int) string {
+	return d.data[d.pos:min(d.pos+n, len(d.data))]
+}
@@ -0,0 +1,156 @@
+package patchpanel
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_decodeTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "scalars",
+			data: "# comment\nname = \"app\" # trailing\nport = 8_080\nmask = 0o755\nflags = 0xff\nratio = 1.5e3\ndebug = true\n",
+			want: map[string]any{"name": "app", "port": int64(8080), "mask": int64(0o755), "flags": int64(255), "ratio": 1500.0, "debug": true},
+		},
+		{
+			name: "strings",
+			data: "basic = \"tab\\tquote\\\" \\u00e9\"\nliteral = 'C:\\path'\nmulti = \"\"\"\nline one \\\n   continued\"\"\"\nraw = '''\nkeep \\n as is'''\n",
+			want: map[string]any{"basic": "tab\tquote\" é", "literal": `C:\path`, "multi": "line one continued", "raw": `keep \n as is`},
+		},
+		{
+			name: "dates and times",
+			data: "offset = 1979-05-27T07:32:00Z\nspaced = 1979-05-27 07:32:00-07:00\nlocal = 1979-05-27\nclock = 07:32:00\n",
+			want: map[string]any{
+				"offset": time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+				"spaced": time.Date(1979, 5, 27, 7, 32, 0, 0, time.FixedZone("", -7*60*60)),
+				"local":  "1979-05-27",
+				"clock":  "07:32:00",
+			},
+		},
+		{
+			name: "arrays and inline tables",
+			data: "hosts = [\n  \"a\", # first\n  \"b\",\n]\npoint = { x = 1, y.z = 2 }\nempty = []\n",
+			want: map[string]any{
+				"hosts": []any{"a", "b"},
+				"point": map[string]any{"x": int64(1), "y": map[string]any{"z": int64(2)}},
+				"empty": []any{},
+			},
+		},
+		{
+			name: "tables",
+			data: "top = 1\n[database.primary]\nhost = \"db1\"\n[database]\nname = \"main\"\n[\"quoted key\"]\nv = 1\n",
+			want: map[string]any{
+				"top":        int64(1),
+				"database":   map[string]any{"primary": map[string]any{"host": "db1"}, "name": "main"},
+				"quoted key": map[string]any{"v": int64(1)},
+			},
+		},
+		{
+			name: "arrays of tables",
+			data: "[[fruit]]\nname = \"apple\"\n[fruit.variety]\nname = \"red\"\n[[fruit]]\nname = \"banana\"\n",
+			want: map[string]any{"fruit": []any{
+				map[string]any{"name": "apple", "variety": map[string]any{"name": "red"}},
+				map[string]any{"name": "banana"},
+			}},
+		},
+		{name: "duplicate key", data: "a = 1\na = 2\n", wantErr: true},
+		{name: "missing equals", data: "a 1\n", wantErr: true},
+		{name: "unterminated string", data: "a = \"open\n", wantErr: true},
+		{name: "trailing garbage", data: "a = 1 2\n", wantErr: true},
+		{name: "invalid escape", data: "a = \"\\q\"\n", wantErr: true},
+		{name: "unclosed header", data: "[a\n", wantErr: true},
+		{name: "value as table", data: "a = 1\n[a]\n", wantErr: true},
+		{name: "table under empty array", data: "a = []\n[a.b]\n", wantErr: true},
+		{name: "array of tables under empty array", data: "a = []\n[[a.b]]\n", wantErr: true},
+		{name: "table under array of values", data: "a = [{ b = 1 }]\n[a.c]\n", wantErr: true},
+		{name: "array of tables extending array of values", data: "a = [{ b = 1 }]\n[[a]]\n", wantErr: true},
+		{name: "table redefined", data: "[a]\nb = 1\n[a]\nc = 2\n", wantErr: true},
+		{name: "array of tables as table", data: "[[a]]\n[a]\n", wantErr: true},
+		{name: "missing value at end", data: "a =", wantErr: true},
+		{name: "missing key at end", data: "[", wantErr: true},
+		{name: "dotted key at end", data: "a.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeTOML(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeTOML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeTOML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	got, err := decodeTOML("a = nan\nb = -inf\n")
+	if err != nil || !math.IsNaN(got["a"].(float64)) || !math.IsInf(got["b"].(float64), -1) {
+		t.Errorf("decodeTOML() special floats = %v, %v", got, err)
+	}
+}
+
+type TOMLConfig struct {
+	Name     string        `toml:"name" default:"app"`
+	Timeout  time.Duration `toml:"timeout" default:"5s"`
+	Start    time.Time     `toml:"start"`
+	Ports    []int         `toml:"ports"`
+	Database struct {
+		Host string `toml:"host" default:"localhost"`
+		Port int    `toml:"port" default:"5432"`
+	} `toml:"database"`
+	Workers map[string]PopulateWorker `toml:"workers" defaultKey:"default"`
+}
+
+func TestPatchPanel_PopulateTOML(t *testing.T) {
+	data := []byte(`
+name = "billing"
+start = 2025-01-02T03:04:05Z
+ports = [8080, 8081]
+
+[database]
+host = "db1"
+
+[workers.default]
+concurrency = 4
+
+[workers.email]
+retries = 1
+`)
+	var conf TOMLConfig
+	if err := New().PopulateTOML(&conf, data); err != nil {
+		t.Fatalf("PopulateTOML() error = %v", err)
+	}
+	if conf.Name != "billing" || conf.Timeout != 5*time.Second || !reflect.DeepEqual(conf.Ports, []int{8080, 8081}) {
+		t.Errorf("PopulateTOML() = %+v", conf)
+	}
+	if !conf.Start.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("PopulateTOML() Start = %v", conf.Start)
+	}
+	if conf.Database.Host != "db1" || conf.Database.Port != 5432 {
+		t.Errorf("PopulateTOML() Database = %+v", conf.Database)
+	}
+	if email := conf.Workers["email"]; email.Concurrency != 4 || email.Retries != 1 {
+		t.Errorf("PopulateTOML() Workers = %+v", conf.Workers)
+	}
+
+	if err := New().PopulateTOML(&conf, []byte("name = \n")); err == nil {
+		t.Errorf("PopulateTOML() of invalid TOML succeeded")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(`name = "from-file"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().PopulateTOMLFile(&conf, path); err != nil || conf.Name != "from-file" {
+		t.Errorf("PopulateTOMLFile() = %v, %v", conf.Name, err)
+	}
+}
@@ -0,0 +1,125 @@
+package patchpanel
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// XMLTextKey is the key under which the text of an XML element with attributes or child elements is kept, e.g.
+// `config:"timeout.#text"` for <timeout unit="s">30</timeout>
+const XMLTextKey = "#text"
+
+// PopulateXML fills dst from an XML document, as PopulateJSON does from JSON.  The root element stands for dst,
+// whatever its name; child elements and attributes are looked up by their field's ConfigTag, `xml` tag name, or
+// name, and nested elements populate nested structs.  Paths in the form `xml:"database>host"` reach into nested
+// elements, as with encoding/xml:
+//
+//	<config>
+//	  <timeout>30s</timeout>
+//	  <database host="db1">
+//	    <port>5432</port>
+//	  </database>
+//	  <port>8080</port>
+//	  <port>8081</port>
+//	</config>
+//
+// Repeated elements populate slices.
+func (pc *PatchPanel) PopulateXML(dst any, data []byte) error {
+	doc, err := decodeXML(data)
+	if err != nil {
+		return fmt.Errorf("decoding XML config: %w", err)
+	}
+	return pc.populateDocument(dst, doc, "xml")
+}
+
+// PopulateXMLFile is PopulateXML for the file at path
+func (pc *PatchPanel) PopulateXMLFile(dst any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return pc.PopulateXML(dst, data)
+}
+
+// decodeXML decodes the root element of an XML document into nested map[string]any objects.  Elements holding
+// only text become strings, and repeated elements become []any lists.
+func decodeXML(data []byte) (map[string]any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		value, err := decodeXMLElement(decoder, start)
+		if err != nil {
+			return nil, err
+		}
+		doc, _ := value.(map[string]any)
+		if doc == nil {
+			doc = make(map[string]any)
+		}
+		return doc, nil
+	}
+}
+
+// decodeXMLElement decodes the element opened by start, up to its end
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (any, error) {
+	obj := make(map[string]any)
+	for _, attr := range start.Attr {
+		// namespace declarations describe the document rather than configure anything
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		obj[attr.Name.Local] = attr.Value
+	}
+	// lists holds the names of children that have repeated, to tell them from single values
+	lists := make(map[string]bool)
+	var text strings.Builder
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			existing, ok := obj[name]
+			switch {
+			case !ok:
+				obj[name] = child
+			case lists[name]:
+				obj[name] = append(existing.([]any), child)
+			default:
+				obj[name] = []any{existing, child}
+				lists[name] = true
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(obj) == 0 {
+				return text.String(), nil
+			}
+			if trimmed := strings.TrimSpace(text.String()); trimmed != "" {
+				obj[XMLTextKey] = trimmed
+			}
+			return obj, nil
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package patchpanel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_decodeXML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "elements and attributes",
+			data: `<?xml version="1.0"?>
+<!-- comment -->
+<config xmlns="urn:example" mode="strict">
+  <name>app</name>
+  <empty/>
+  <escaped><![CDATA[a < b]]> &amp; c</escaped>
+  <database host="db1"><port>5432</port></database>
+</config>`,
+			want: map[string]any{
+				"mode":     "strict",
+				"name":     "app",
+				"empty":    "",
+				"escaped":  "a < b & c",
+				"database": map[string]any{"host": "db1", "port": "5432"},
+			},
+		},
+		{
+			name: "repeated elements",
+			data: "<config><port>1</port><port>2</port><port>3</port><worker><id>a</id></worker><worker><id>b</id></worker></config>",
+			want: map[string]any{
+				"port":   []any{"1", "2", "3"},
+				"worker": []any{map[string]any{"id": "a"}, map[string]any{"id": "b"}},
+			},
+		},
+		{
+			name: "text beside attributes",
+			data: `<config><timeout unit="s"> 30 </timeout></config>`,
+			want: map[string]any{"timeout": map[string]any{"unit": "s", "#text": "30"}},
+		},
+		{name: "text root", data: "<config>text</config>", want: map[string]any{}},
+		{name: "empty document", data: "<!-- nothing -->", wantErr: true},
+		{name: "unclosed element", data: "<config><name>app</config>", wantErr: true},
+		{name: "truncated", data: "<config><name>app", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeXML([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeXML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeXML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type XMLConfig struct {
+	Name     string        `xml:"name" default:"app"`
+	Timeout  time.Duration `xml:"timeout" default:"5s"`
+	Ports    []int         `xml:"port"`
+	Host     string        `xml:"database>host" default:"localhost"`
+	Database struct {
+		Port int    `xml:"port" default:"5432"`
+		User string `xml:"user,attr" default:"app"`
+	} `xml:"database"`
+	Workers map[string]PopulateWorker `xml:"workers" defaultKey:"default"`
+}
+
+func TestPatchPanel_PopulateXML(t *testing.T) {
+	data := []byte(`
+<config>
+  <name>billing</name>
+  <port>8080</port>
+  <port>8081</port>
+  <database host="db1" user="billing"/>
+  <workers>
+    <default><concurrency>4</concurrency></default>
+    <email><retries>1</retries></email>
+  </workers>
+</config>`)
+	var conf XMLConfig
+	if err := New().PopulateXML(&conf, data); err != nil {
+		t.Fatalf("PopulateXML() error = %v", err)
+	}
+	if conf.Name != "billing" || conf.Timeout != 5*time.Second || !reflect.DeepEqual(conf.Ports, []int{8080, 8081}) {
+		t.Errorf("PopulateXML() = %+v", conf)
+	}
+	if conf.Host != "db1" || conf.Database.Port != 5432 || conf.Database.User != "billing" {
+		t.Errorf("PopulateXML() Host = %v, Database = %+v", conf.Host, conf.Database)
+	}
+	if email := conf.Workers["email"]; email.Concurrency != 4 || email.Retries != 1 {
+		t.Errorf("PopulateXML() Workers = %+v", conf.Workers)
+	}
+
+	if err := New().PopulateXML(&conf, []byte("<config><name>")); err == nil {
+		t.Errorf("PopulateXML() of invalid XML succeeded")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.xml")
+	if err := os.WriteFile(path, []byte("<config><name>from-file</name></config>"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().PopulateXMLFile(&conf, path); err != nil || conf.Name != "from-file" {
+		t.Errorf("PopulateXMLFile() = %v, %v", conf.Name, err)
+	}
+}
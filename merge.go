@@ -0,0 +1,143 @@
+package patchpanel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergeReport records where each value of a merged configuration came from, to debug which file a setting was
+// taken from
+type MergeReport struct {
+	// Files are the merged files, in the order they were applied
+	Files []string
+	// Values lists every value of the merged document, sorted by path
+	Values []MergedValue
+}
+
+// MergedValue is a single value of a merged document
+type MergedValue struct {
+	// Path is the dotted key of the value, e.g. "database.port"
+	Path string
+	// File is the file the value was taken from
+	File string
+	// Overridden lists the earlier files whose value at Path was replaced, oldest first
+	Overridden []string
+}
+
+// String lists each value's path and file, one per line, noting the files it overrode
+func (r MergeReport) String() string {
+	var b strings.Builder
+	for _, v := range r.Values {
+		fmt.Fprintf(&b, "%s: %s", v.Path, v.File)
+		if len(v.Overridden) > 0 {
+			fmt.Fprintf(&b, " (overrides %s)", strings.Join(v.Overridden, ", "))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// MergeFiles reads configuration files as FileSource does and deep-merges them into a single Source, each file
+// overriding the ones before it, so a base file can be followed by an environment's overlay:
+//
+//	file, report, err := patchpanel.MergeFiles("config.toml", "config.production.toml")
+//
+// Objects are merged key by key, and any other value, including a list, replaces the earlier one whole.  Keys
+// are matched case-insensitively, as fields are.  The files must share a format, as their keys are read from the
+// same format tag.  Files listed under IncludeKey are merged in beneath the file including them, and reported as
+// the source of their own values.  Values it supplies are reported as SourceFile.
+func MergeFiles(paths ...string) (Source, MergeReport, error) {
+	report := MergeReport{Files: paths}
+	if len(paths) == 0 {
+		return nil, report, fmt.Errorf("no configuration files to merge")
+	}
+
+	merged := make(map[string]any)
+	values := make(map[string]*MergedValue)
+	var formatTag string
+	for _, path := range paths {
+		fileFormatTag, err := loadDocument(path, nil, merged, values)
+		if err != nil {
+			return nil, report, err
+		}
+		if formatTag != "" && fileFormatTag != formatTag {
+			return nil, report, fmt.Errorf("%s: cannot merge %s with %s files", path, fileFormatTag, formatTag)
+		}
+		formatTag = fileFormatTag
+	}
+
+	for _, v := range values {
+		report.Values = append(report.Values, *v)
+	}
+	sort.Slice(report.Values, func(i, j int) bool { return report.Values[i].Path < report.Values[j].Path })
+	return DocumentSource(SourceFile, merged, formatTag), report, nil
+}
+
+// mergeDocument deep-merges src, read from file, into dst, recording the file of each value under prefix in
+// values.  dst's objects are always its own, so decoded documents are never modified.
+func mergeDocument(dst, src map[string]any, prefix, file string, values map[string]*MergedValue) {
+	for k, v := range src {
+		key := k
+		existing, ok := dst[k]
+		if !ok {
+			for dk, dv := range dst {
+				if strings.EqualFold(dk, k) {
+					// the later spelling wins, so lookups preferring an exact match can't find the stale one
+					key, existing, ok = dk, dv, true
+					delete(dst, dk)
+					break
+				}
+			}
+		}
+		path := fieldPath(prefix, key)
+		target := fieldPath(prefix, k)
+
+		if obj, isObj := v.(map[string]any); isObj {
+			existingObj, existingIsObj := existing.(map[string]any)
+			if !existingIsObj {
+				// an object replacing a value starts afresh
+				if ok {
+					dropMergedValues(values, path)
+				}
+				existingObj = make(map[string]any)
+			} else if key != k {
+				renameMergedValues(values, path, target)
+			}
+			dst[k] = existingObj
+			mergeDocument(existingObj, obj, target, file, values)
+			continue
+		}
+
+		var overridden []string
+		if ok {
+			if prev, found := values[path]; found {
+				overridden = append(prev.Overridden, prev.File)
+			}
+			dropMergedValues(values, path)
+		}
+		dst[k] = v
+		values[target] = &MergedValue{Path: target, File: file, Overridden: overridden}
+	}
+}
+
+// dropMergedValues forgets the value at path and every value nested under it
+func dropMergedValues(values map[string]*MergedValue, path string) {
+	for p := range values {
+		if p == path || strings.HasPrefix(p, path+".") {
+			delete(values, p)
+		}
+	}
+}
+
+// renameMergedValues moves the values nested under from to the same keys under to, as an object's key is
+// respelled
+func renameMergedValues(values map[string]*MergedValue, from, to string) {
+	for p, v := range values {
+		if strings.HasPrefix(p, from+".") {
+			delete(values, p)
+			v.Path = to + strings.TrimPrefix(p, from)
+			values[v.Path] = v
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package patchpanel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.toml": `
+name = "billing"
+ports = [8080, 8081]
+
+[database]
+host = "db1"
+port = 5432
+
+[workers.email]
+retries = 1
+`,
+		"production.toml": `
+Ports = [443]
+
+[database]
+host = "db-prod"
+
+[workers.sms]
+retries = 2
+`,
+		"local.toml": `
+database = "sqlite"
+`,
+		"base.json": `{"name": "json"}`,
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := filepath.Join(dir, "base.toml")
+	production := filepath.Join(dir, "production.toml")
+
+	src, report, err := MergeFiles(base, production)
+	if err != nil {
+		t.Fatalf("MergeFiles() error = %v", err)
+	}
+	var conf TOMLConfig
+	if err := New().PopulateFrom(&conf, src); err != nil {
+		t.Fatalf("PopulateFrom(MergeFiles()) error = %v", err)
+	}
+	if conf.Name != "billing" || !reflect.DeepEqual(conf.Ports, []int{443}) {
+		t.Errorf("PopulateFrom(MergeFiles()) = %+v", conf)
+	}
+	if conf.Database.Host != "db-prod" || conf.Database.Port != 5432 {
+		t.Errorf("PopulateFrom(MergeFiles()) Database = %+v", conf.Database)
+	}
+	if conf.Workers["email"].Retries != 1 || conf.Workers["sms"].Retries != 2 {
+		t.Errorf("PopulateFrom(MergeFiles()) Workers = %+v", conf.Workers)
+	}
+
+	wantValues := []MergedValue{
+		{Path: "Ports", File: production, Overridden: []string{base}},
+		{Path: "database.host", File: production, Overridden: []string{base}},
+		{Path: "database.port", File: base},
+		{Path: "name", File: base},
+		{Path: "workers.email.retries", File: base},
+		{Path: "workers.sms.retries", File: production},
+	}
+	if !reflect.DeepEqual(report.Values, wantValues) {
+		t.Errorf("MergeFiles() report = %+v, want %+v", report.Values, wantValues)
+	}
+	if want := "database.host: " + production + " (overrides " + base + ")\n"; !strings.Contains(report.String(), want) {
+		t.Errorf("MergeReport.String() = %q, missing %q", report.String(), want)
+	}
+
+	// a value replacing an object drops the object's values
+	_, report, err = MergeFiles(base, filepath.Join(dir, "local.toml"))
+	if err != nil {
+		t.Fatalf("MergeFiles() error = %v", err)
+	}
+	for _, v := range report.Values {
+		if v.Path == "database.host" || v.Path == "database.port" {
+			t.Errorf("MergeFiles() report kept %s after it was replaced", v.Path)
+		}
+	}
+
+	for _, paths := range [][]string{
+		nil,
+		{base, filepath.Join(dir, "base.json")},
+		{base, filepath.Join(dir, "missing.toml")},
+		{filepath.Join(dir, "base.yaml")},
+	} {
+		if _, _, err := MergeFiles(paths...); err == nil {
+			t.Errorf("MergeFiles(%v) succeeded", paths)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package patchpanel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cloud providers supported by CloudMetadata
+const (
+	MetadataGCE   = "gce"
+	MetadataEC2   = "ec2"
+	MetadataAzure = "azure"
+)
+
+// metadataAddress is the link-local address every supported provider serves metadata from
+const metadataAddress = "http://169.254.169.254"
+
+const (
+	defaultMetadataTimeout  = 2 * time.Second
+	defaultMetadataCacheTTL = 5 * time.Minute
+	// the EC2 session token is requested for six hours and renewed a minute early
+	ec2TokenTTL = 6 * time.Hour
+)
+
+// ErrMetadataNotFound is returned when the metadata server has no value for a key
+var ErrMetadataNotFound = errors.New("metadata key not found")
+
+// CloudMetadata reads instance metadata and user-data from the GCE, EC2 or Azure metadata server, so that
+// instance-specific settings can be resolved at startup instead of being baked into images.
+//
+// Keys are paths relative to the provider's metadata root:
+//
+//	gce    computeMetadata/v1/<key>, e.g. "instance/attributes/region"
+//	ec2    latest/<key>, e.g. "meta-data/instance-id" or "user-data" (IMDSv2 session tokens are handled)
+//	azure  metadata/<key>, e.g. "instance/compute/location"
+//
+// Requests are bounded by Timeout and successful values are cached for CacheTTL.
+type CloudMetadata struct {
+	provider string
+	// Endpoint is the metadata server's base URL, overridable for testing or proxies
+	Endpoint string
+	// Timeout bounds each request to the metadata server; zero uses the 2s default
+	Timeout time.Duration
+	// CacheTTL is how long values are reused before being fetched again; zero disables caching
+	CacheTTL time.Duration
+	// Client is the HTTP client used for requests; nil uses http.DefaultClient
+	Client *http.Client
+
+	mu          sync.Mutex
+	cache       map[string]cachedMetadata
+	ec2Token    string
+	ec2TokenExp time.Time
+}
+
+type cachedMetadata struct {
+	value   string
+	expires time.Time
+}
+
+// NewCloudMetadata creates a metadata reader for provider (MetadataGCE, MetadataEC2 or MetadataAzure)
+// with short default timeouts and caching.
+func NewCloudMetadata(provider string) (*CloudMetadata, error) {
+	switch provider {
+	case MetadataGCE, MetadataEC2, MetadataAzure:
+	default:
+		return nil, fmt.Errorf("unknown metadata provider %q, expected gce, ec2 or azure", provider)
+	}
+	return &CloudMetadata{
+		provider: provider,
+		Endpoint: metadataAddress,
+		Timeout:  defaultMetadataTimeout,
+		CacheTTL: defaultMetadataCacheTTL,
+		Client:   &http.Client{},
+		cache:    make(map[string]cachedMetadata),
+	}, nil
+}
+
+// MetadataTag names the tag holding a field's metadata key, e.g. `metadata:"instance/attributes/region"`
+const MetadataTag = "metadata"
+
+// Source is a Source reading each field from the metadata key named by mapper, using ctx for its requests; a nil
+// mapper reads keys from MetadataTag:
+//
+//	err := pc.PopulateFrom(&conf, patchpanel.EnvSource(), cm.Source(ctx, nil))
+//
+// Keys the server doesn't have fall through to later sources, while other failures, such as timeouts off the
+// cloud, fail the field.  Values are reported as SourceMetadata.
+func (cm *CloudMetadata) Source(ctx context.Context, mapper NameMapper) Source {
+	if mapper == nil {
+		mapper = TagNames(MetadataTag)
+	}
+	return mappedSource{name: SourceMetadata, mapper: mapper, lookup: func(key string) (string, bool, error) {
+		value, err := cm.Get(ctx, key)
+		if errors.Is(err, ErrMetadataNotFound) {
+			return "", false, nil
+		}
+		return value, err == nil, err
+	}}
+}
+
+// Get returns the metadata value for key
+func (cm *CloudMetadata) Get(ctx context.Context, key string) (string, error) {
+	key = strings.TrimPrefix(key, "/")
+
+	cm.mu.Lock()
+	cached, ok := cm.cache[key]
+	cm.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	timeout := cm.Timeout
+	if timeout <= 0 {
+		timeout = defaultMetadataTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	value, err := cm.fetch(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	if cm.CacheTTL > 0 {
+		cm.mu.Lock()
+		cm.cache[key] = cachedMetadata{value: value, expires: time.Now().Add(cm.CacheTTL)}
+		cm.mu.Unlock()
+	}
+	return value, nil
+}
+
+func (cm *CloudMetadata) fetch(ctx context.Context, key string) (string, error) {
+	var req *http.Request
+	var err error
+
+	switch cm.provider {
+	case MetadataGCE:
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, cm.Endpoint+"/computeMetadata/v1/"+key, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+	case MetadataEC2:
+		token, err := cm.ec2SessionToken(ctx)
+		if err != nil {
+			return "", err
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, cm.Endpoint+"/latest/"+key, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+	case MetadataAzure:
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, cm.Endpoint+"/metadata/"+key+"?api-version=2021-02-01&format=text", nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata", "true")
+	}
+
+	return cm.do(req)
+}
+
+// ec2SessionToken returns a cached IMDSv2 token, requesting a new one when it is about to expire
+func (cm *CloudMetadata) ec2SessionToken(ctx context.Context) (string, error) {
+	cm.mu.Lock()
+	token, expires := cm.ec2Token, cm.ec2TokenExp
+	cm.mu.Unlock()
+	if token != "" && time.Now().Before(expires) {
+		return token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, cm.Endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", fmt.Sprint(int(ec2TokenTTL/time.Second)))
+	token, err = cm.do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting EC2 metadata token: %w", err)
+	}
+
+	cm.mu.Lock()
+	cm.ec2Token, cm.ec2TokenExp = token, time.Now().Add(ec2TokenTTL-time.Minute)
+	cm.mu.Unlock()
+	return token, nil
+}
+
+func (cm *CloudMetadata) do(req *http.Request) (string, error) {
+	client := cm.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%w: %s", ErrMetadataNotFound, req.URL.Path)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("metadata server returned %s for %s", resp.Status, req.URL.Path)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,114 @@
+package patchpanel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCloudMetadata(t *testing.T) {
+	var requests, tokens atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case r.URL.Path == "/computeMetadata/v1/instance/attributes/region" && r.Header.Get("Metadata-Flavor") == "Google":
+			_, _ = w.Write([]byte("us-central1"))
+		case r.URL.Path == "/latest/api/token" && r.Method == http.MethodPut:
+			tokens.Add(1)
+			_, _ = w.Write([]byte("token-123"))
+		case r.URL.Path == "/latest/meta-data/instance-id" && r.Header.Get("X-aws-ec2-metadata-token") == "token-123":
+			_, _ = w.Write([]byte("i-0abc"))
+		case r.URL.Path == "/latest/user-data" && r.Header.Get("X-aws-ec2-metadata-token") == "token-123":
+			_, _ = w.Write([]byte("#cloud-config"))
+		case r.URL.Path == "/metadata/instance/compute/location" && r.Header.Get("Metadata") == "true" &&
+			r.URL.Query().Get("format") == "text":
+			_, _ = w.Write([]byte("eastus"))
+		case r.URL.Path == "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	newMetadata := func(provider string) *CloudMetadata {
+		cm, err := NewCloudMetadata(provider)
+		if err != nil {
+			t.Fatalf("NewCloudMetadata() error = %v", err)
+		}
+		cm.Endpoint = server.URL
+		return cm
+	}
+	ctx := context.Background()
+
+	gce := newMetadata(MetadataGCE)
+	if got, err := gce.Get(ctx, "instance/attributes/region"); err != nil || got != "us-central1" {
+		t.Errorf("gce Get() = %q, %v", got, err)
+	}
+
+	ec2 := newMetadata(MetadataEC2)
+	if got, err := ec2.Get(ctx, "meta-data/instance-id"); err != nil || got != "i-0abc" {
+		t.Errorf("ec2 Get() = %q, %v", got, err)
+	}
+	if got, err := ec2.Get(ctx, "user-data"); err != nil || got != "#cloud-config" {
+		t.Errorf("ec2 Get(user-data) = %q, %v", got, err)
+	}
+	if tokens.Load() != 1 {
+		t.Errorf("ec2 session token requested %d times, want 1", tokens.Load())
+	}
+
+	azure := newMetadata(MetadataAzure)
+	if got, err := azure.Get(ctx, "/instance/compute/location"); err != nil || got != "eastus" {
+		t.Errorf("azure Get() = %q, %v", got, err)
+	}
+
+	// cached values don't reach the server
+	before := requests.Load()
+	if _, err := gce.Get(ctx, "instance/attributes/region"); err != nil || requests.Load() != before {
+		t.Errorf("gce Get() was not served from cache")
+	}
+
+	if _, err := gce.Get(ctx, "instance/attributes/missing"); !errors.Is(err, ErrMetadataNotFound) {
+		t.Errorf("gce Get(missing) error = %v, want ErrMetadataNotFound", err)
+	}
+
+	slow := newMetadata(MetadataGCE)
+	slow.Endpoint = server.URL + "/slow?"
+	slow.Timeout = 20 * time.Millisecond
+	if _, err := slow.Get(ctx, "x"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want deadline exceeded", err)
+	}
+
+	// a zero timeout falls back to the default rather than failing every request
+	unbounded := newMetadata(MetadataGCE)
+	unbounded.Timeout = 0
+	unbounded.Client = nil
+	if got, err := unbounded.Get(ctx, "instance/attributes/region"); err != nil || got != "us-central1" {
+		t.Errorf("Get() with a zero Timeout = %q, %v", got, err)
+	}
+
+	type MetadataConfig struct {
+		Region string `metadata:"instance/attributes/region"`
+		Zone   string `metadata:"instance/attributes/zone" default:"a"`
+		Name   string `default:"app"`
+	}
+	var conf MetadataConfig
+	if err := New().PopulateFrom(&conf, gce.Source(ctx, nil)); err != nil {
+		t.Fatalf("PopulateFrom(Source()) error = %v", err)
+	}
+	if conf != (MetadataConfig{Region: "us-central1", Zone: "a", Name: "app"}) {
+		t.Errorf("PopulateFrom(Source()) = %+v", conf)
+	}
+	var fieldErr FieldError
+	if err := New().PopulateFrom(&conf, slow.Source(ctx, nil)); !errors.As(err, &fieldErr) || fieldErr.Source != SourceMetadata {
+		t.Errorf("PopulateFrom(Source()) error = %v, want a metadata FieldError", err)
+	}
+
+	if _, err := NewCloudMetadata("openstack"); err == nil {
+		t.Errorf("NewCloudMetadata() expected error for unknown provider")
+	}
+}
@@ -0,0 +1,140 @@
+package patchpanel
+
+import (
+	"log"
+	"reflect"
+)
+
+// DefaultValueTag is the tag Populate reads values from unless configured otherwise with WithValueTag.
+const DefaultValueTag = "default"
+
+// Option configures a PatchPanel created with New.
+type Option func(*PatchPanel)
+
+// WithTokenSeparator sets the separator used to split entries inside a tag.  See TokenSeparator.
+func WithTokenSeparator(separator string) Option {
+	return func(pc *PatchPanel) {
+		pc.tokenSeparator = separator
+	}
+}
+
+// WithKeyValueSeparator sets the separator used to split key/value entries inside a tag.  See KeyValueSeparator.
+func WithKeyValueSeparator(separator string) Option {
+	return func(pc *PatchPanel) {
+		pc.keyValueSeparator = separator
+	}
+}
+
+// WithValueTag sets the tag Populate reads field values from, e.g. WithValueTag("fallback")
+// populates `fallback:"5s"`.
+func WithValueTag(tagName string) Option {
+	return func(pc *PatchPanel) {
+		pc.valueTag = tagName
+	}
+}
+
+// WithProfile selects the value tags of profile, e.g. "prod", so that one struct can describe every environment:
+// fields read `default_prod:"db.internal:5432"` when it is present, and fall back to `default:"localhost:5432"`.
+// Profile tags follow the value tag set with WithValueTag, as in `fallback_prod`.  An empty profile reads only the
+// value tag.
+func WithProfile(profile string) Option {
+	return func(pc *PatchPanel) {
+		pc.profile = profile
+	}
+}
+
+// WithParser registers a parser at construction time.  It is equivalent to calling AddParser after New.
+func WithParser(typ reflect.Type, parser Parser) Option {
+	return func(pc *PatchPanel) {
+		pc.parsers[typ] = parser.hintsParser()
+	}
+}
+
+// WithTemplateVars sets the variables substituted into path templates, e.g. {"app": "billing"} for
+// `default:"/var/log/${app}/${hostname}.log" pathTemplate:"true"`.  ${hostname} is provided by default.
+func WithTemplateVars(vars map[string]string) Option {
+	return func(pc *PatchPanel) {
+		pc.templateVars = vars
+	}
+}
+
+// WithIgnoreUnknownTypes makes Populate skip tagged fields whose type has no parser instead of failing, so that
+// patchpanel can be adopted incrementally in large structs.  Each skipped field is passed to warn as a FieldError
+// wrapping an UnhandledParserTypeError; a nil warn logs them with the standard logger.
+//
+// Parse failures on supported types are still returned, and the getters remain strict.
+func WithIgnoreUnknownTypes(warn func(FieldError)) Option {
+	if warn == nil {
+		warn = func(fieldErr FieldError) {
+			log.Printf("patchpanel: skipping field: %v", fieldErr)
+		}
+	}
+	return func(pc *PatchPanel) {
+		pc.unknownTypeWarn = warn
+	}
+}
+
+// WithLimits bounds the work Populate will do, so that pathological structs or values can't exhaust memory.
+// See Limits.
+func WithLimits(limits Limits) Option {
+	return func(pc *PatchPanel) {
+		pc.limits = limits
+	}
+}
+
+// WithCache caches the parsed values of types, in addition to any already cached, so that repeated values are
+// parsed once.  Cached values are shared between fields, so only types whose values are immutable or safe to
+// share should be listed.  Without types, *regexp.Regexp values are cached.  A nil cache uses an LRUCache of
+// DefaultCacheSize.
+//
+// The cache is purged whenever a parser is added.
+func WithCache(cache Cache, types ...reflect.Type) Option {
+	if cache == nil {
+		cache = NewLRUCache(DefaultCacheSize)
+	}
+	if len(types) == 0 {
+		types = defaultCachedTypes
+	}
+	return func(pc *PatchPanel) {
+		pc.cache = cache
+		pc.cacheTypes(types...)
+	}
+}
+
+// WithInterning reduces the memory held by many populated instances (e.g. per tenant or per request) by
+// interning string values with the unique package, and sharing parsed *regexp.Regexp, *time.Location and url.URL
+// values through the panel's cache.  A cache is created with NewLRUCache if WithCache isn't also used.
+func WithInterning() Option {
+	return func(pc *PatchPanel) {
+		pc.intern = true
+		if pc.cache == nil {
+			pc.cache = NewLRUCache(DefaultCacheSize)
+		}
+		pc.cacheTypes(internedTypes...)
+	}
+}
+
+// WithDebug selects how programmer errors are surfaced: a non-pointer passed to Populate, a nil Option, or a
+// value tag on an unexported field.  In debug mode they panic with the offending type or field, so they're caught
+// early in development; otherwise they are returned as errors, or for unexported fields skipped.
+//
+// Debug mode defaults to off, or to on in builds with the patchpanel_debug build tag.
+func WithDebug(enabled bool) Option {
+	return func(pc *PatchPanel) {
+		pc.debug = enabled
+	}
+}
+
+// MessageCatalog rewrites the message of a parse or validation error, e.g. to translate it for operators.  It
+// receives the error's ErrorCode and the error itself, and returns the replacement message, or "" to keep the
+// original.
+type MessageCatalog func(code ErrorCode, err error) string
+
+// WithMessageCatalog routes parse and validation errors through catalog, so their text can be translated or
+// rewritten per deployment.  Rewritten errors are LocalizedErrors wrapping the original, so errors.As and the
+// codes in an ErrorReport are unchanged.  A field's `errmsg` tag still leads the rewritten message.
+func WithMessageCatalog(catalog MessageCatalog) Option {
+	return func(pc *PatchPanel) {
+		pc.messages = catalog
+	}
+}
@@ -1,12 +1,23 @@
 package patchpanel
 
 import (
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
+	htmltemplate "html/template"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	texttemplate "text/template"
 	"time"
 )
 
@@ -54,89 +65,259 @@ var timeFormatMap = map[string]string{
 	"TimeOnly":    time.TimeOnly,
 }
 
+// Parser converts a raw value into a field's type.  parserHints are only valid for the duration of the call, as
+// Populate reuses them for other fields; copy anything that needs to outlive it.  Hints(parserHints) provides the
+// typed getters, or register a HintsParser with AddHintsParser to receive Hints directly.
 type Parser func(value string, parserHints map[string]any) (any, error)
 
+// HintsParser is a Parser receiving the field's hints as Hints
+type HintsParser func(value string, parserHints Hints) (any, error)
+
+// TargetParser is a parser that serves a set of related types, e.g. every integer width.  It receives the
+// concrete destination type so that one implementation can range check and convert for each of them.
+type TargetParser func(value string, toType reflect.Type, parserHints Hints) (any, error)
+
 type PatchPanel struct {
 	tokenSeparator    string
 	keyValueSeparator string
-	parsers           map[reflect.Type]Parser
+	// valueTag is the tag Populate reads field values from
+	valueTag string
+	// profile, when set, selects profile value tags over valueTag, see WithProfile
+	profile string
+	// templateVars are substituted into `pathTemplate:"true"` strings
+	templateVars map[string]string
+	// unknownTypeWarn, when set, receives fields Populate skipped for lack of a parser instead of failing
+	unknownTypeWarn func(FieldError)
+	// limits bound the work done by a single Populate
+	limits Limits
+	// cache, when set, holds parsed values of cachedTypes
+	cache       Cache
+	cachedTypes map[reflect.Type]bool
+	// intern reports whether parsed strings are interned
+	intern bool
+	// debug makes programmer errors panic instead of returning them, see WithDebug
+	debug bool
+	// messages, when set, rewrites the text of parse and validation errors
+	messages MessageCatalog
+	// patterns holds compiled `pattern` hints so that repeated populates don't recompile them
+	patterns map[string]*regexp.Regexp
+	parsers  map[reflect.Type]HintsParser
 	sync.Mutex
 }
 
-// NewPatchPanel instantiates a PatchPanel
+// NewPatchPanel instantiates a PatchPanel.
+//
+// NewPatchPanel is the v1 constructor and is kept as an adapter around New; it is equivalent to
+// New(WithTokenSeparator(tokenSeparator), WithKeyValueSeparator(keyValueSeparator)).
 func NewPatchPanel(tokenSeparator string, keyValueSeparator string) *PatchPanel {
+	return New(WithTokenSeparator(tokenSeparator), WithKeyValueSeparator(keyValueSeparator))
+}
+
+// New instantiates a PatchPanel configured by opts.  Without options, the panel uses TokenSeparator,
+// KeyValueSeparator, and populates fields from their `default` tag.
+func New(opts ...Option) *PatchPanel {
 	pc := &PatchPanel{
-		tokenSeparator:    tokenSeparator,
-		keyValueSeparator: keyValueSeparator,
+		tokenSeparator:    TokenSeparator,
+		keyValueSeparator: KeyValueSeparator,
+		valueTag:          DefaultValueTag,
 		// Parsers are looked up via reflect.Types instead of "standard" types as the pipeline starts at
 		// StructField.Types.  Using reflect.Type vs specific reflect.Kind allows for arbitrary user
 		// types to be added (reflect.TypeOf(Foo) vs being restricted to reflect.Kind).
 		//
 		// note that parser hints are per field
-		parsers: map[reflect.Type]Parser{
-			// str
-			reflect.TypeOf(""): func(v string, parserHints map[string]any) (any, error) {
-				return v, nil
-			},
-
-			// bool
-			reflect.TypeOf(true): func(v string, parserHints map[string]any) (any, error) {
-				return strconv.ParseBool(v)
-			},
-
-			// int
-			reflect.TypeOf(0): func(v string, parserHints map[string]any) (any, error) {
-				return strconv.Atoi(v)
-			},
-
-			// time.Duration
-			reflect.TypeOf(time.Duration(0)): func(v string, parserHints map[string]any) (any, error) {
-				val, err := time.ParseDuration(v)
-				if err != nil {
-					return time.Duration(0), err
-				}
-				return val, nil
-			},
-
-			// time.Time
-			reflect.TypeOf(time.Time{}): func(v string, parserHints map[string]any) (any, error) {
-				// timeFormatString is required as the go compiler
-				// cannot infer that timeFormat will invariably become a string
-				var timeFormatString string
-				// did the user request a time format?
-				timeFormatHint, ok := parserHints["timeFormat"]
-				// if we haven't been told how to parse this time, try RFC 3339
-				if !ok {
-					timeFormatString = time.RFC3339
-				} else {
-					// any->str
-					tFormatHintStr, ok := timeFormatHint.(string)
-					if !ok {
-						return time.Time{}, errors.New("timeFormat parser hint must be a string")
-					}
-					// do we have a `time` package const that corresponds with the request string?
-					timeFormatString, ok = timeFormatMap[tFormatHintStr]
-					if !ok {
-						return time.Time{}, errors.New("unknown timeFormat provided")
-					}
-				}
-				val, err := time.Parse(timeFormatString, v)
-				if err != nil {
-					return time.Time{}, err
-				}
-				return val, nil
-			},
+		parsers: map[reflect.Type]HintsParser{
+			// bool, with `boolStyle:"lenient"` accepting yes/no, on/off, enabled/disabled
+			reflect.TypeOf(true): parseBool,
+
+			// *big.Int, *big.Float, *big.Rat
+			reflect.TypeOf(&big.Int{}):   parseBigInt,
+			reflect.TypeOf(&big.Float{}): parseBigFloat,
+			reflect.TypeOf(&big.Rat{}):   parseBigRat,
+
+			// ByteSize
+			reflect.TypeOf(ByteSize(0)): parseByteSize,
+
+			// time.Duration, with `durationUnits:"extended"` adding days and weeks
+			reflect.TypeOf(time.Duration(0)): parseDuration,
+
+			// *time.Location
+			reflect.TypeOf(time.UTC): parseLocation,
+
+			// time.Weekday
+			reflect.TypeOf(time.Sunday): parseWeekday,
+
+			// SemVer
+			reflect.TypeOf(SemVer{}): parseSemVerHinted,
+
+			// UUID, [16]byte
+			reflect.TypeOf(UUID{}):     parseUUID,
+			reflect.TypeOf([16]byte{}): parseUUIDBytes,
+
+			// []byte, decoded according to the `encoding` hint
+			reflect.TypeOf([]byte{}): parseBytes,
+
+			// json.RawMessage
+			reflect.TypeOf(json.RawMessage{}): parseRawMessage,
+
+			// *regexp.Regexp
+			reflect.TypeOf(&regexp.Regexp{}): parseRegexp,
+
+			// *text/template.Template, *html/template.Template, and Template with the `templateEngine` hint
+			reflect.TypeOf(&texttemplate.Template{}): parseTextTemplate,
+			reflect.TypeOf(&htmltemplate.Template{}): parseHTMLTemplate,
+			reflect.TypeFor[Template]():              parseTemplate,
+
+			// os.FileMode
+			reflect.TypeOf(os.FileMode(0)): parseFileMode,
+
+			// LatLng
+			reflect.TypeOf(LatLng{}): parseLatLng,
+
+			// net.IPNet, *net.IPNet, netip.Prefix
+			reflect.TypeOf(net.IPNet{}):    parseIPNet,
+			reflect.TypeOf(&net.IPNet{}):   parseIPNetPtr,
+			reflect.TypeOf(netip.Prefix{}): parsePrefix,
+
+			// host:port
+			reflect.TypeOf(HostPort{}):       parseHostPort,
+			reflect.TypeOf(netip.AddrPort{}): parseAddrPort,
+
+			// mail.Address, *mail.Address
+			reflect.TypeOf(mail.Address{}):  parseMailAddress,
+			reflect.TypeOf(&mail.Address{}): parseMailAddressPtr,
+
+			// ListenAddr
+			reflect.TypeOf(ListenAddr{}): parseListenAddr,
+
+			// TLSVersion, CipherSuite
+			reflect.TypeOf(TLSVersion(0)):  parseTLSVersion,
+			reflect.TypeOf(CipherSuite(0)): parseCipherSuite,
 		},
 		Mutex: sync.Mutex{},
 	}
+
+	// str, which may be a path template
+	pc.parsers[reflect.TypeOf("")] = pc.parseString
+
+	// int and uint of every width, float32, float64
+	for _, typ := range numericTypes {
+		pc.parsers[typ] = targetParser(parseNumber, typ)
+	}
+
+	// parsers that split on the token separator this panel was configured with, or the `sep` hint
+	pc.parsers[reflect.TypeOf([]net.IPNet{})] = pc.parseIPNets
+	pc.parsers[reflect.TypeOf([]*net.IPNet{})] = pc.parseIPNetPtrs
+	pc.parsers[reflect.TypeOf([]netip.Prefix{})] = pc.parsePrefixes
+	pc.parsers[reflect.TypeOf(time.Time{})] = pc.parseTime
+	pc.parsers[reflect.TypeOf(WeekdaySet(0))] = pc.parseWeekdaySet
+	pc.parsers[reflect.TypeOf([]time.Weekday{})] = pc.parseWeekdays
+	pc.parsers[reflect.TypeOf(MaintenanceWindow{})] = pc.parseMaintenanceWindow
+	pc.parsers[reflect.TypeOf([]mail.Address{})] = pc.parseMailAddresses
+	pc.parsers[reflect.TypeOf([]*mail.Address{})] = pc.parseMailAddressPtrs
+	pc.parsers[reflect.TypeOf(url.URL{})] = pc.parseURLValue
+	pc.parsers[reflect.TypeOf(&url.URL{})] = pc.parseURLPtr
+
+	// parsers that split on the key/value separator
+	pc.parsers[reflect.TypeOf(tls.Certificate{})] = pc.parseCertificate
+	pc.parsers[reflect.TypeOf(&tls.Certificate{})] = pc.parseCertificatePtr
+
+	// Range[T] of the common number types
+	registerRanges(pc)
+
+	pc.debug = debugBuild
+	nilOption := false
+	for _, opt := range opts {
+		if opt == nil {
+			nilOption = true
+			continue
+		}
+		opt(pc)
+	}
+	// checked once every option, including WithDebug, has been applied
+	if nilOption {
+		_ = pc.misuse(errors.New("nil Option passed to New"))
+	}
+
 	return pc
 }
 
 // AddParser adds a parser configuration.  The ability to overwrite is intentional.
 func (pc *PatchPanel) AddParser(typ reflect.Type, parser Parser) {
+	pc.AddHintsParser(typ, parser.hintsParser())
+}
+
+// AddHintsParser is AddParser for a parser receiving Hints
+func (pc *PatchPanel) AddHintsParser(typ reflect.Type, parser HintsParser) {
 	pc.Lock()
 	defer pc.Unlock()
 	pc.parsers[typ] = parser
+	pc.purgeCache()
+}
+
+// AddTargetParser registers one parser for several destination types.  Each call to the parser receives
+// the type being populated.  As with AddParser, existing registrations are overwritten.
+func (pc *PatchPanel) AddTargetParser(parser TargetParser, types ...reflect.Type) {
+	pc.Lock()
+	defer pc.Unlock()
+	for _, typ := range types {
+		pc.parsers[typ] = targetParser(parser, typ)
+	}
+	pc.purgeCache()
+}
+
+// cacheTypes adds types to those held in the panel's cache
+func (pc *PatchPanel) cacheTypes(types ...reflect.Type) {
+	if pc.cachedTypes == nil {
+		pc.cachedTypes = make(map[reflect.Type]bool, len(types))
+	}
+	for _, typ := range types {
+		pc.cachedTypes[typ] = true
+	}
+}
+
+// purgeCache drops cached values, which may have been produced by a parser that has since been replaced
+func (pc *PatchPanel) purgeCache() {
+	if pc.cache != nil {
+		pc.cache.Purge()
+	}
+}
+
+// AddTypedParser registers fn as the parser for T, deriving the reflect.Type from T.  Because fn returns a T,
+// the parser can't accidentally produce a value of the wrong type, and callers don't need to box results in any.
+//
+//	patchpanel.AddTypedParser(pp, func(v string, hints patchpanel.Hints) (time.Month, error) { ... })
+func AddTypedParser[T any](pp *PatchPanel, fn func(string, Hints) (T, error)) {
+	pp.AddHintsParser(reflect.TypeFor[T](), func(value string, parserHints Hints) (any, error) {
+		return fn(value, parserHints)
+	})
+}
+
+// hintsParser adapts parser to the HintsParser the panel stores
+func (parser Parser) hintsParser() HintsParser {
+	return func(value string, parserHints Hints) (any, error) {
+		return parser(value, parserHints)
+	}
+}
+
+// targetParser binds a TargetParser to a single destination type
+func targetParser(parser TargetParser, typ reflect.Type) HintsParser {
+	return func(value string, parserHints Hints) (any, error) {
+		return parser(value, typ, parserHints)
+	}
+}
+
+// parser looks up the registered parser for typ.  Unregistered slice types are served by their element
+// type's parser, split on the field's separator.
+func (pc *PatchPanel) parser(typ reflect.Type) (HintsParser, bool) {
+	pc.Lock()
+	defer pc.Unlock()
+	parserFunc, ok := pc.parsers[typ]
+	if !ok && typ.Kind() == reflect.Slice {
+		if elemParser, elemOK := pc.parsers[typ.Elem()]; elemOK {
+			return pc.sliceParser(typ, elemParser), true
+		}
+	}
+	return parserFunc, ok
 }
 
 // ToReflectType is a shallow wrapper around reflect.TypeOf, placed in this library for reasons of code-flow
@@ -155,11 +336,143 @@ func FieldNameById(obj reflect.Type, idx int) string {
 	return obj.Field(idx).Name
 }
 
-func parseHints(sF reflect.StructField, hints []string) map[string]any {
+// AllTags returns the tag of every tagged field of the struct type t, keyed by field name, so that frameworks
+// building on patchpanel can read the whole tag surface in one pass.  Pointers to structs are dereferenced, and a
+// nil map is returned for other types.
+func AllTags(t reflect.Type) map[string]reflect.StructTag {
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	tags := make(map[string]reflect.StructTag, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if sF := t.Field(i); sF.Tag != "" {
+			tags[sF.Name] = sF.Tag
+		}
+	}
+	return tags
+}
+
+// AllTagValues is AllTags with each tag split into its key:"value" entries, e.g.
+// {"Port": {"default": "8080", "min": "1024"}}.
+func AllTagValues(t reflect.Type) map[string]map[string]string {
+	tags := AllTags(t)
+	if tags == nil {
+		return nil
+	}
+
+	values := make(map[string]map[string]string, len(tags))
+	for name, tag := range tags {
+		fieldValues := make(map[string]string)
+		eachTagPair(tag, func(key, value string) {
+			fieldValues[key] = value
+		})
+		values[name] = fieldValues
+	}
+	return values
+}
+
+// tagPair is a single key:"value" entry of a struct tag
+type tagPair struct {
+	key   string
+	value string
+}
+
+// tagPairs splits a struct tag into its key:"value" entries, in declaration order.
+// Malformed trailing content is ignored, matching reflect.StructTag.Lookup.
+func tagPairs(tag reflect.StructTag) []tagPair {
+	var pairs []tagPair
+	eachTagPair(tag, func(key, value string) {
+		pairs = append(pairs, tagPair{key: key, value: value})
+	})
+	return pairs
+}
+
+// eachTagPair calls fn with each key:"value" entry of a struct tag, in declaration order, without allocating
+// the entries as tagPairs does
+func eachTagPair(tag reflect.StructTag, fn func(key, value string)) {
+	for tag != "" {
+		// skip leading space
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// scan to colon; a space, a quote or a control character is a syntax error
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := string(tag[:i])
+		tag = tag[i+1:]
+
+		// scan quoted string to find value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		qvalue := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			break
+		}
+		fn(name, value)
+	}
+}
+
+// fieldHints uses every tag on a field as a parser hint.  Populate uses this so that struct authors
+// don't need to enumerate hint names.
+func fieldHints(sF reflect.StructField) Hints {
+	return fillFieldHints(make(Hints), sF)
+}
+
+func fillFieldHints(parserHintTable Hints, sF reflect.StructField) Hints {
+	eachTagPair(sF.Tag, func(key, value string) {
+		parserHintTable[key] = strings.TrimSpace(value)
+	})
+	return parserHintTable
+}
+
+// hintsPool recycles the hint maps Populate builds for each field, which would otherwise be garbage after
+// every parse
+var hintsPool = sync.Pool{
+	New: func() any { return make(Hints) },
+}
+
+// pooledFieldHints is fieldHints backed by hintsPool.  Return the hints with releaseHints once the parser has
+// returned.
+func pooledFieldHints(sF reflect.StructField) Hints {
+	return fillFieldHints(hintsPool.Get().(Hints), sF)
+}
+
+func releaseHints(parserHints Hints) {
+	clear(parserHints)
+	hintsPool.Put(parserHints)
+}
+
+func parseHints(sF reflect.StructField, hints []string) Hints {
 
 	// if we have parser hints, cleanup and split into a map
 	// parser hints likely originate from a tag
-	parserHintTable := make(map[string]any)
+	parserHintTable := make(Hints)
 
 	for _, hintTag := range hints {
 		tagValue := sF.Tag.Get(hintTag)
@@ -173,16 +486,14 @@ func parseHints(sF reflect.StructField, hints []string) map[string]any {
 // coerce converts an input to a desired destination type specified by toType
 // We expect our input value, v, to be a string as we expect to be handling struct tags
 // parserHints are optional and come in as a string from a tag name
-func (pc *PatchPanel) coerce(v string, toType reflect.Type, parserHints map[string]any) (any, error) {
-	pc.Lock()
-	defer pc.Unlock()
-
-	parserFunc, ok := pc.parsers[toType]
+func (pc *PatchPanel) coerce(v string, toType reflect.Type, parserHints Hints) (any, error) {
+	// the lock only guards the registry; parsers run unlocked so they may call back into the panel
+	parserFunc, ok := pc.parser(toType)
 	if !ok {
-		return nil, UnhandledParserTypeError{Msg: fmt.Sprintf("unknown type for parser: %v", reflect.TypeOf(v))}
+		return nil, UnhandledParserTypeError{Msg: fmt.Sprintf("unknown type for parser: %v", toType)}
 	}
 
-	val, err := parserFunc(v, parserHints)
+	val, err := pc.parse(parserFunc, v, toType, parserHints)
 	if err != nil {
 		// return whatever type parserFunc uses
 		return val, err
@@ -227,6 +538,45 @@ func (pc *PatchPanel) GetFieldTag(fieldName string, tagName string, t reflect.Ty
 	return sF, val, nil
 }
 
+// GetFieldTags loads several tags off of a given field in a struct, coercing each to the field's type, e.g. the
+// `min`, `max` and `default` tags of an int field.  The field, its parser and its hints are looked up once.
+//
+// Tags the field doesn't have are left out of the returned map.  On a coercion failure, the tags coerced so far
+// are returned along with the error.
+func (pc *PatchPanel) GetFieldTags(fieldName string, tagNames []string, t reflect.Type, parserHints []string) (map[string]any, error) {
+	if t == nil {
+		return nil, errors.New("nil type provided")
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct type, got %s", t.Kind().String())
+	}
+
+	sF, ok := t.FieldByName(fieldName)
+	if !ok {
+		return nil, NoFieldError{Msg: "no such field name: " + fieldName}
+	}
+
+	parserFunc, ok := pc.parser(sF.Type)
+	if !ok {
+		return nil, UnhandledParserTypeError{Msg: fmt.Sprintf("unknown type for parser: %v", sF.Type)}
+	}
+	hints := parseHints(sF, parserHints)
+
+	values := make(map[string]any, len(tagNames))
+	for _, tagName := range tagNames {
+		raw, ok := sF.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		val, err := pc.parse(parserFunc, raw, sF.Type, hints)
+		if err != nil {
+			return values, fmt.Errorf("tag %s: %w", tagName, err)
+		}
+		values[tagName] = val
+	}
+	return values, nil
+}
+
 // GetDefault retrieves the field tag called 'default' and extracts the value
 func (pc *PatchPanel) GetDefault(fieldName string, t reflect.Type, parserHints []string) (any, error) {
 
@@ -0,0 +1,46 @@
+package patchpanel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lenientBools are the words accepted by the lenient bool style in addition to strconv.ParseBool's forms
+var lenientBools = map[string]bool{
+	"yes":      true,
+	"y":        true,
+	"on":       true,
+	"enable":   true,
+	"enabled":  true,
+	"no":       false,
+	"n":        false,
+	"off":      false,
+	"disable":  false,
+	"disabled": false,
+}
+
+// ParseLenientBool accepts the forms understood by strconv.ParseBool as well as yes/no, y/n, on/off and
+// enabled/disabled, case-insensitively, as operators commonly write them in environment variables.
+func ParseLenientBool(v string) (bool, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if b, ok := lenientBools[v]; ok {
+		return b, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+// parseBool handles bool using strconv.ParseBool, or ParseLenientBool with the `boolStyle:"lenient"` hint
+func parseBool(v string, parserHints Hints) (any, error) {
+	style, err := parserHints.GetString("boolStyle")
+	if err != nil {
+		return false, err
+	}
+	switch style {
+	case "", "strict":
+		return strconv.ParseBool(v)
+	case "lenient":
+		return ParseLenientBool(v)
+	}
+	return false, fmt.Errorf("unknown boolStyle %q, expected strict or lenient", style)
+}
@@ -0,0 +1,51 @@
+package patchpanel
+
+import (
+	"testing"
+)
+
+type BoolStruct struct {
+	Strict       bool `default:"true"`
+	StrictYes    bool `default:"yes"`
+	LenientYes   bool `default:"Yes" boolStyle:"lenient"`
+	LenientOff   bool `default:"OFF" boolStyle:"lenient"`
+	LenientOn    bool `default:" enabled " boolStyle:"lenient"`
+	LenientNo    bool `default:"disabled" boolStyle:"lenient"`
+	LenientOne   bool `default:"1" boolStyle:"lenient"`
+	LenientMaybe bool `default:"maybe" boolStyle:"lenient"`
+	UnknownStyle bool `default:"true" boolStyle:"fuzzy"`
+}
+
+func Test_boolParser(t *testing.T) {
+	pp := New()
+	bs := ToReflectType(BoolStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      bool
+		wantErr   bool
+	}{
+		{name: "strict", fieldName: "Strict", want: true},
+		{name: "strict rejects yes", fieldName: "StrictYes", wantErr: true},
+		{name: "lenient yes", fieldName: "LenientYes", want: true},
+		{name: "lenient off", fieldName: "LenientOff", want: false},
+		{name: "lenient enabled", fieldName: "LenientOn", want: true},
+		{name: "lenient disabled", fieldName: "LenientNo", want: false},
+		{name: "lenient keeps strict forms", fieldName: "LenientOne", want: true},
+		{name: "lenient unknown word", fieldName: "LenientMaybe", wantErr: true},
+		{name: "unknown style", fieldName: "UnknownStyle", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, bs, []string{"boolStyle"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package patchpanel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseOctalMode reads permissions such as "0640", "640", "0o640" or "4755"; the leading digit of a
+// four digit mode sets the setuid (4), setgid (2) and sticky (1) bits
+func parseOctalMode(v string) (os.FileMode, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(v, "0o"), "0O")
+	bits, err := strconv.ParseUint(digits, 8, 32)
+	if err != nil || bits > 0o7777 {
+		return 0, fmt.Errorf("invalid octal file mode %q", v)
+	}
+
+	mode := os.FileMode(bits & 0o777)
+	if bits&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode, nil
+}
+
+// parseSymbolicMode reads permissions in the form shown by ls, e.g. "rw-r-----" or "-rwxr-sr-t".
+// A leading file type character, if present, must be "-" as only permissions are configurable.
+func parseSymbolicMode(v string) (os.FileMode, error) {
+	perms := v
+	if len(perms) == 10 {
+		if perms[0] != '-' {
+			return 0, fmt.Errorf("invalid symbolic file mode %q, only permissions may be set", v)
+		}
+		perms = perms[1:]
+	}
+	if len(perms) != 9 {
+		return 0, fmt.Errorf("invalid symbolic file mode %q", v)
+	}
+
+	var mode os.FileMode
+	for i := 0; i < 9; i++ {
+		bit := os.FileMode(1) << (8 - i)
+		c := perms[i]
+		switch {
+		case c == '-':
+		case c == "rwx"[i%3]:
+			mode |= bit
+		// the execute position also carries setuid, setgid and sticky: lower case when executable
+		case i == 2 && (c == 's' || c == 'S'):
+			mode |= os.ModeSetuid
+		case i == 5 && (c == 's' || c == 'S'):
+			mode |= os.ModeSetgid
+		case i == 8 && (c == 't' || c == 'T'):
+			mode |= os.ModeSticky
+		default:
+			return 0, fmt.Errorf("invalid symbolic file mode %q", v)
+		}
+		if c == 's' || c == 't' {
+			mode |= bit
+		}
+	}
+	return mode, nil
+}
+
+// parseFileMode handles os.FileMode permissions in octal ("0640") or symbolic ("rw-r-----") form
+func parseFileMode(v string, parserHints Hints) (any, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return os.FileMode(0), errors.New("empty file mode")
+	}
+	if v[0] >= '0' && v[0] <= '9' {
+		return parseOctalMode(v)
+	}
+	return parseSymbolicMode(v)
+}
+
+// parseString handles plain strings, which are used as is unless path hints are present:
+//
+//	pathTemplate:"true"  expands ${name} variables (see WithTemplateVars, ${hostname} is built in)
+//	mkdirs:"0750"        marks the path's parent directories for creation by EnsureDirs, with the given mode
+//
+// With either hint, the final value must be a clean, absolute path.  Parsing never touches the filesystem, so
+// GetFieldTag, Schema and a Populate that fails elsewhere leave no directories behind.
+func (pc *PatchPanel) parseString(v string, parserHints Hints) (any, error) {
+	isTemplate, err := parserHints.GetBool("pathTemplate")
+	if err != nil {
+		return "", err
+	}
+	if !isTemplate && !parserHints.Has("mkdirs") {
+		return v, nil
+	}
+
+	path := v
+	if isTemplate {
+		path, err = pc.expandPathTemplate(v)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path %q is not absolute", path)
+	}
+	if clean := filepath.Clean(path); clean != path {
+		return "", fmt.Errorf("path %q is not clean, expected %q", path, clean)
+	}
+
+	if parserHints.Has("mkdirs") {
+		modeStr, err := parserHints.GetString("mkdirs")
+		if err != nil {
+			return "", err
+		}
+		if _, err := parseOctalMode(modeStr); err != nil {
+			return "", fmt.Errorf("mkdirs parser hint: %w", err)
+		}
+	}
+
+	return path, nil
+}
+
+// EnsureDirs creates the parent directories of the paths in src, a populated struct or pointer to one, whose
+// fields carry the mkdirs hint.  It is the explicit step after a successful Populate that parsing leaves out:
+//
+//	if err := pc.Populate(&conf); err != nil {
+//		return err
+//	}
+//	if err := pc.EnsureDirs(&conf); err != nil {
+//		return err
+//	}
+//
+// Fields are walked as Populate walks them; string and []string fields are supported, and empty paths skipped.
+// Each failure is reported as a FieldError, and all failures are joined into the returned error.
+func (pc *PatchPanel) EnsureDirs(src any) error {
+	rv := reflect.ValueOf(src)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return pc.misuse(InvalidTargetError{Msg: fmt.Sprintf("EnsureDirs source must be a struct or pointer to one, got %T", src)})
+	}
+	var errs []error
+	pc.ensureDirs(rv, "", map[uintptr]bool{}, &errs)
+	return errors.Join(errs...)
+}
+
+// ensureDirs creates the directories of the struct rv at prefix.  seen holds the pointers already followed, so
+// that pointer cycles terminate.
+func (pc *PatchPanel) ensureDirs(rv reflect.Value, prefix string, seen map[uintptr]bool, errs *[]error) {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		sF := rt.Field(i)
+		if !sF.IsExported() && !sF.Anonymous {
+			continue
+		}
+		path := fieldPath(prefix, sF.Name)
+		fieldValue := rv.Field(i)
+
+		if modeStr, ok := sF.Tag.Lookup("mkdirs"); ok {
+			var paths []string
+			switch {
+			case sF.Type.Kind() == reflect.String:
+				paths = []string{fieldValue.String()}
+			case sF.Type.Kind() == reflect.Slice && sF.Type.Elem().Kind() == reflect.String:
+				for j := 0; j < fieldValue.Len(); j++ {
+					paths = append(paths, fieldValue.Index(j).String())
+				}
+			}
+			mode, err := parseOctalMode(modeStr)
+			for _, dirPath := range paths {
+				if dirPath == "" {
+					continue
+				}
+				if err == nil {
+					err = os.MkdirAll(filepath.Dir(dirPath), mode)
+				}
+				if err != nil {
+					*errs = append(*errs, pc.fieldError(sF, path, dirPath, "", err))
+					break
+				}
+			}
+			continue
+		}
+
+		if _, ok := pc.parser(sF.Type); ok {
+			continue
+		}
+		switch {
+		case sF.Type.Kind() == reflect.Struct:
+			pc.ensureDirs(fieldValue, path, seen, errs)
+		case sF.Type.Kind() == reflect.Pointer && sF.Type.Elem().Kind() == reflect.Struct:
+			pc.ensureDirsAt(fieldValue, path, seen, errs)
+		case pc.isStructMap(sF.Type):
+			for _, key := range fieldValue.MapKeys() {
+				pc.ensureDirsAt(fieldValue.MapIndex(key), fieldPath(path, key.String()), seen, errs)
+			}
+		}
+	}
+}
+
+// ensureDirsAt creates the directories of v at path, a struct or a pointer to one, following each pointer once
+func (pc *PatchPanel) ensureDirsAt(v reflect.Value, path string, seen map[uintptr]bool, errs *[]error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() || seen[v.Pointer()] {
+			return
+		}
+		seen[v.Pointer()] = true
+		v = v.Elem()
+	}
+	pc.ensureDirs(v, path, seen, errs)
+}
+
+// expandPathTemplate substitutes ${name} variables, failing on any that are unknown
+func (pc *PatchPanel) expandPathTemplate(v string) (string, error) {
+	var missing []string
+	expanded := os.Expand(v, func(name string) string {
+		if value, ok := pc.templateVars[name]; ok {
+			return value
+		}
+		if name == "hostname" {
+			hostname, err := os.Hostname()
+			if err == nil {
+				return hostname
+			}
+		}
+		missing = append(missing, name)
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unknown path template variables: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
@@ -0,0 +1,163 @@
+package patchpanel
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type FileModeStruct struct {
+	Log       os.FileMode `default:"0640"`
+	NoZero    os.FileMode `default:"755"`
+	Prefixed  os.FileMode `default:"0o600"`
+	Setuid    os.FileMode `default:"4755"`
+	Symbolic  os.FileMode `default:"rw-r-----"`
+	LsStyle   os.FileMode `default:"-rwxr-sr-t"`
+	Directory os.FileMode `default:"drwxr-xr-x"`
+	NotOctal  os.FileMode `default:"0648"`
+	TooLarge  os.FileMode `default:"17777"`
+	Garbage   os.FileMode `default:"rw-rw-rwz"`
+}
+
+func Test_fileModeParser(t *testing.T) {
+	pp := New()
+	fs := ToReflectType(FileModeStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      os.FileMode
+		wantErr   bool
+	}{
+		{name: "octal", fieldName: "Log", want: 0o640},
+		{name: "octal without leading zero", fieldName: "NoZero", want: 0o755},
+		{name: "0o prefix", fieldName: "Prefixed", want: 0o600},
+		{name: "setuid", fieldName: "Setuid", want: os.ModeSetuid | 0o755},
+		{name: "symbolic", fieldName: "Symbolic", want: 0o640},
+		{name: "ls style with setgid and sticky", fieldName: "LsStyle", want: os.ModeSetgid | os.ModeSticky | 0o755},
+		{name: "file type rejected", fieldName: "Directory", wantErr: true},
+		{name: "invalid octal digit", fieldName: "NotOctal", wantErr: true},
+		{name: "too many bits", fieldName: "TooLarge", wantErr: true},
+		{name: "invalid symbol", fieldName: "Garbage", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, fs, []string{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pathTemplate(t *testing.T) {
+	root := t.TempDir()
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip("hostname unavailable")
+	}
+	pp := New(WithTemplateVars(map[string]string{"root": root, "app": "billing"}))
+
+	var dst struct {
+		Log      string `default:"${root}/log/${app}/${hostname}.log" pathTemplate:"true" mkdirs:"0750"`
+		Pid      string `default:"${root}/run/${app}.pid" pathTemplate:"true"`
+		Literal  string `default:"${root}/literal"`
+		Relative string `default:"log/${app}.log" pathTemplate:"true"`
+		Unclean  string `default:"${root}/log/../${app}.log" pathTemplate:"true"`
+		Unknown  string `default:"${root}/${region}.log" pathTemplate:"true"`
+		BadMode  string `default:"/tmp/x" mkdirs:"rwx"`
+	}
+	err = pp.Populate(&dst)
+
+	wantLog := filepath.Join(root, "log", "billing", hostname+".log")
+	if dst.Log != wantLog {
+		t.Errorf("Populate() Log = %v, want %v", dst.Log, wantLog)
+	}
+	if _, statErr := os.Stat(filepath.Join(root, "log")); !os.IsNotExist(statErr) {
+		t.Errorf("Populate() created a directory while parsing")
+	}
+	if dst.Pid != filepath.Join(root, "run", "billing.pid") {
+		t.Errorf("Populate() Pid = %v", dst.Pid)
+	}
+	if _, statErr := os.Stat(filepath.Join(root, "run")); !os.IsNotExist(statErr) {
+		t.Errorf("Populate() created a directory without the mkdirs hint")
+	}
+	if dst.Literal != "${root}/literal" {
+		t.Errorf("Populate() expanded a string without the pathTemplate hint: %v", dst.Literal)
+	}
+
+	wantFailed := map[string]bool{"Relative": true, "Unclean": true, "Unknown": true, "BadMode": true}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fieldErr FieldError
+		if errors.As(e, &fieldErr) {
+			delete(wantFailed, fieldErr.Field)
+		}
+	}
+	if len(wantFailed) > 0 {
+		t.Errorf("Populate() did not report failures for %v: %v", wantFailed, err)
+	}
+}
+
+type EnsureDirsConfig struct {
+	Log     string   `default:"${root}/log/app.log" pathTemplate:"true" mkdirs:"0750"`
+	Spools  []string `mkdirs:"0700"`
+	Empty   string   `mkdirs:"0750"`
+	Nested  *EnsureDirsConfig
+	Workers map[string]struct {
+		Data string `mkdirs:"0750"`
+	}
+}
+
+func TestPatchPanel_EnsureDirs(t *testing.T) {
+	root := t.TempDir()
+	pp := New(WithTemplateVars(map[string]string{"root": root}))
+
+	// parsing alone, by the getters or a schema, has no filesystem side effects
+	typ := reflect.TypeFor[EnsureDirsConfig]()
+	if _, _, err := pp.GetFieldTag("Log", "default", typ, []string{}); err != nil {
+		t.Fatalf("GetFieldTag() error = %v", err)
+	}
+	if _, err := pp.Schema(EnsureDirsConfig{}); err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	var conf EnsureDirsConfig
+	if err := pp.Populate(&conf); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 0 {
+		t.Fatalf("parsing created %v", entries)
+	}
+
+	conf.Spools = []string{filepath.Join(root, "spool", "a", "queue"), filepath.Join(root, "spool", "b", "queue")}
+	conf.Nested = &EnsureDirsConfig{Log: filepath.Join(root, "nested", "app.log")}
+	conf.Nested.Nested = &conf // pointer cycles are followed once
+	conf.Workers = map[string]struct {
+		Data string `mkdirs:"0750"`
+	}{"email": {Data: filepath.Join(root, "workers", "email", "data.db")}}
+	if err := pp.EnsureDirs(&conf); err != nil {
+		t.Fatalf("EnsureDirs() error = %v", err)
+	}
+	for _, dir := range []string{"log", "spool/a", "spool/b", "nested", "workers/email"} {
+		if info, err := os.Stat(filepath.Join(root, dir)); err != nil || !info.IsDir() {
+			t.Errorf("EnsureDirs() did not create %s: %v", dir, err)
+		}
+	}
+
+	var bad struct {
+		Path string `mkdirs:"rwx"`
+	}
+	bad.Path = filepath.Join(root, "bad", "x")
+	var fieldErr FieldError
+	if err := pp.EnsureDirs(bad); !errors.As(err, &fieldErr) || fieldErr.Field != "Path" {
+		t.Errorf("EnsureDirs() error = %v, want a FieldError for Path", err)
+	}
+	if err := New(WithDebug(false)).EnsureDirs(42); !errors.As(err, new(InvalidTargetError)) {
+		t.Errorf("EnsureDirs() error = %v, want InvalidTargetError", err)
+	}
+}
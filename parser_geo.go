@@ -0,0 +1,129 @@
+package patchpanel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LatLng is a geographic coordinate in decimal degrees.
+//
+// It parses from "41.88,-87.63", or with the `coordFormat:"dms"` hint from degrees, minutes and seconds with
+// hemisphere letters, e.g. "41°52'48\"N 87°37'48\"W" or "41 52 48 N, 87 37 48 W".
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// String renders the coordinate as "lat,lng"
+func (ll LatLng) String() string {
+	return strconv.FormatFloat(ll.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(ll.Lng, 'f', -1, 64)
+}
+
+func (ll LatLng) validate() error {
+	if ll.Lat < -90 || ll.Lat > 90 {
+		return fmt.Errorf("latitude %v out of range [-90, 90]", ll.Lat)
+	}
+	if ll.Lng < -180 || ll.Lng > 180 {
+		return fmt.Errorf("longitude %v out of range [-180, 180]", ll.Lng)
+	}
+	return nil
+}
+
+// parseDecimalLatLng reads "lat,lng"
+func parseDecimalLatLng(v string) (LatLng, error) {
+	lat, lng, ok := strings.Cut(v, ",")
+	if !ok {
+		return LatLng{}, fmt.Errorf("invalid coordinate %q, expected \"lat,lng\"", v)
+	}
+	var ll LatLng
+	var err error
+	if ll.Lat, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil {
+		return LatLng{}, fmt.Errorf("invalid latitude %q", lat)
+	}
+	if ll.Lng, err = strconv.ParseFloat(strings.TrimSpace(lng), 64); err != nil {
+		return LatLng{}, fmt.Errorf("invalid longitude %q", lng)
+	}
+	return ll, nil
+}
+
+// parseDMSLatLng reads a latitude and longitude, each as up to three numbers (degrees, minutes, seconds)
+// followed by a hemisphere letter
+func parseDMSLatLng(v string) (LatLng, error) {
+	normalized := strings.NewReplacer("°", " ", "′", " ", "″", " ", "'", " ", "\"", " ", ",", " ").Replace(v)
+	var (
+		parts      []float64
+		components [2]float64
+		seen       int
+	)
+	for _, token := range strings.Fields(normalized) {
+		// a hemisphere letter may be attached to the last number, e.g. 48N
+		hemisphere := ""
+		if last := token[len(token)-1]; strings.ContainsRune("NSEWnsew", rune(last)) {
+			hemisphere = strings.ToUpper(string(last))
+			token = token[:len(token)-1]
+		}
+		if token != "" {
+			n, err := strconv.ParseFloat(token, 64)
+			if err != nil || n < 0 {
+				return LatLng{}, fmt.Errorf("invalid coordinate component %q in %q", token, v)
+			}
+			parts = append(parts, n)
+		}
+		if hemisphere == "" {
+			continue
+		}
+
+		if seen > 1 || len(parts) == 0 || len(parts) > 3 {
+			return LatLng{}, fmt.Errorf("invalid coordinate %q", v)
+		}
+		wantLat := seen == 0
+		if isLat := hemisphere == "N" || hemisphere == "S"; isLat != wantLat {
+			return LatLng{}, fmt.Errorf("invalid coordinate %q, expected latitude (N/S) then longitude (E/W)", v)
+		}
+		degrees := parts[0]
+		for i, divisor := range []float64{60, 3600} {
+			if i+1 < len(parts) {
+				if parts[i+1] >= 60 {
+					return LatLng{}, fmt.Errorf("invalid minutes or seconds in %q", v)
+				}
+				degrees += parts[i+1] / divisor
+			}
+		}
+		if hemisphere == "S" || hemisphere == "W" {
+			degrees = -degrees
+		}
+		components[seen] = degrees
+		seen++
+		parts = nil
+	}
+	if seen != 2 || len(parts) != 0 {
+		return LatLng{}, fmt.Errorf("invalid coordinate %q", v)
+	}
+	return LatLng{Lat: components[0], Lng: components[1]}, nil
+}
+
+// parseLatLng handles LatLng values, selecting the format with the `coordFormat` hint ("decimal" or "dms")
+func parseLatLng(v string, parserHints Hints) (any, error) {
+	format, err := parserHints.GetString("coordFormat")
+	if err != nil {
+		return LatLng{}, err
+	}
+
+	var ll LatLng
+	switch format {
+	case "", "decimal":
+		ll, err = parseDecimalLatLng(v)
+	case "dms":
+		ll, err = parseDMSLatLng(v)
+	default:
+		return LatLng{}, fmt.Errorf("unknown coordFormat %q, expected decimal or dms", format)
+	}
+	if err != nil {
+		return LatLng{}, err
+	}
+	if err := ll.validate(); err != nil {
+		return LatLng{}, err
+	}
+	return ll, nil
+}
@@ -0,0 +1,67 @@
+package patchpanel
+
+import (
+	"math"
+	"testing"
+)
+
+type GeoStruct struct {
+	Chicago     LatLng `default:"41.88,-87.63"`
+	Spaced      LatLng `default:" -33.8688 , 151.2093 "`
+	DMS         LatLng `default:"41°52'48\"N 87°37'48\"W" coordFormat:"dms"`
+	DMSPlain    LatLng `default:"33 52 7.7 S, 151 12 33.5 E" coordFormat:"dms"`
+	DMSDegrees  LatLng `default:"45N 90W" coordFormat:"dms"`
+	Swapped     LatLng `default:"87°37'48\"W 41°52'48\"N" coordFormat:"dms"`
+	BadMinutes  LatLng `default:"41°75'00\"N 87°37'48\"W" coordFormat:"dms"`
+	OutOfRange  LatLng `default:"91,0"`
+	LngRange    LatLng `default:"0,181"`
+	NoComma     LatLng `default:"41.88 -87.63"`
+	UnknownForm LatLng `default:"41.88,-87.63" coordFormat:"utm"`
+}
+
+func Test_latLngParser(t *testing.T) {
+	pp := New()
+	gs := ToReflectType(GeoStruct{})
+	hints := []string{"coordFormat"}
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      LatLng
+		wantErr   bool
+	}{
+		{name: "decimal", fieldName: "Chicago", want: LatLng{Lat: 41.88, Lng: -87.63}},
+		{name: "decimal with spaces", fieldName: "Spaced", want: LatLng{Lat: -33.8688, Lng: 151.2093}},
+		{name: "dms symbols", fieldName: "DMS", want: LatLng{Lat: 41.88, Lng: -87.63}},
+		{name: "dms plain", fieldName: "DMSPlain", want: LatLng{Lat: -(33 + 52.0/60 + 7.7/3600), Lng: 151 + 12.0/60 + 33.5/3600}},
+		{name: "dms degrees only", fieldName: "DMSDegrees", want: LatLng{Lat: 45, Lng: -90}},
+		{name: "dms longitude first", fieldName: "Swapped", wantErr: true},
+		{name: "dms minutes out of range", fieldName: "BadMinutes", wantErr: true},
+		{name: "latitude out of range", fieldName: "OutOfRange", wantErr: true},
+		{name: "longitude out of range", fieldName: "LngRange", wantErr: true},
+		{name: "missing comma", fieldName: "NoComma", wantErr: true},
+		{name: "unknown format", fieldName: "UnknownForm", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, gs, hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			ll := got.(LatLng)
+			if math.Abs(ll.Lat-tt.want.Lat) > 1e-9 || math.Abs(ll.Lng-tt.want.Lng) > 1e-9 {
+				t.Errorf("GetDefault() got = %v, want %v", ll, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatLng_String(t *testing.T) {
+	if got := (LatLng{Lat: 41.88, Lng: -87.63}).String(); got != "41.88,-87.63" {
+		t.Errorf("LatLng.String() = %v", got)
+	}
+}
@@ -0,0 +1,441 @@
+package patchpanel
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a count of bytes parsed from human-readable sizes such as "512KiB", "10MB" or "1.5G".
+//
+// Suffixes are case-insensitive.  Two-letter SI suffixes (KB, MB, GB, TB, PB, EB) are powers of 1000;
+// IEC suffixes (KiB, MiB, ...) and single-letter suffixes (K, M, G, ...) are powers of 1024.
+// A bare number or a "B" suffix is a count of bytes.
+type ByteSize int64
+
+const (
+	Byte ByteSize = 1
+	KiB           = 1024 * Byte
+	MiB           = 1024 * KiB
+	GiB           = 1024 * MiB
+	TiB           = 1024 * GiB
+	PiB           = 1024 * TiB
+	EiB           = 1024 * PiB
+
+	KB = 1000 * Byte
+	MB = 1000 * KB
+	GB = 1000 * MB
+	TB = 1000 * GB
+	PB = 1000 * TB
+	EB = 1000 * PB
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KiB,
+	"kib": KiB,
+	"kb":  KB,
+	"m":   MiB,
+	"mib": MiB,
+	"mb":  MB,
+	"g":   GiB,
+	"gib": GiB,
+	"gb":  GB,
+	"t":   TiB,
+	"tib": TiB,
+	"tb":  TB,
+	"p":   PiB,
+	"pib": PiB,
+	"pb":  PB,
+	"e":   EiB,
+	"eib": EiB,
+	"eb":  EB,
+}
+
+// String renders the size using the largest IEC unit that represents it exactly, e.g. "512KiB".
+func (b ByteSize) String() string {
+	units := []struct {
+		suffix string
+		size   ByteSize
+	}{
+		{"EiB", EiB}, {"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB},
+	}
+	for _, unit := range units {
+		if b != 0 && b%unit.size == 0 {
+			return strconv.FormatInt(int64(b/unit.size), 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// ParseByteSize converts a human-readable size into a ByteSize.  See ByteSize for accepted suffixes.
+func ParseByteSize(v string) (ByteSize, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, errors.New("empty byte size")
+	}
+
+	// split the numeric prefix from the unit suffix
+	i := 0
+	for i < len(v) && (v[i] >= '0' && v[i] <= '9' || v[i] == '.' || v[i] == '-' || v[i] == '+') {
+		i++
+	}
+	number, suffix := v[:i], strings.ToLower(strings.TrimSpace(v[i:]))
+
+	unit, ok := byteSizeUnits[suffix]
+	if !ok {
+		return 0, fmt.Errorf("unknown byte size unit %q in %q", v[i:], v)
+	}
+
+	// whole numbers are computed exactly
+	if whole, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if whole < 0 {
+			return 0, fmt.Errorf("negative byte size %q", v)
+		}
+		if whole > math.MaxInt64/int64(unit) {
+			return 0, fmt.Errorf("byte size %q overflows int64", v)
+		}
+		return ByteSize(whole) * unit, nil
+	}
+
+	fraction, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", v)
+	}
+	if fraction < 0 {
+		return 0, fmt.Errorf("negative byte size %q", v)
+	}
+	size := fraction * float64(unit)
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("byte size %q overflows int64", v)
+	}
+	return ByteSize(math.Round(size)), nil
+}
+
+func parseByteSize(v string, parserHints Hints) (any, error) {
+	return ParseByteSize(v)
+}
+
+// unitIsBytes reports whether the `unit:"bytes"` hint requests byte size parsing for a plain integer field
+func unitIsBytes(parserHints Hints) (bool, error) {
+	unit, err := parserHints.GetString("unit")
+	if err != nil || unit == "" {
+		return false, err
+	}
+	if unit != "bytes" {
+		return false, fmt.Errorf("unknown unit %q", unit)
+	}
+	return true, nil
+}
+
+// ParsePercent reads a percentage ("85%") or a fraction ("0.85") as a fraction between 0 and 1, as used for
+// sampling rates and resource thresholds
+func ParsePercent(v string) (float64, error) {
+	v = strings.TrimSpace(v)
+	number, isPercent := strings.CutSuffix(v, "%")
+	f, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q", v)
+	}
+	if isPercent {
+		f /= 100
+	}
+	if !(f >= 0 && f <= 1) {
+		return 0, fmt.Errorf("percentage %q out of range 0%%-100%%", v)
+	}
+	return f, nil
+}
+
+// numericTypes are the destination types served by parseNumber
+var numericTypes = []reflect.Type{
+	reflect.TypeOf(int(0)),
+	reflect.TypeOf(int8(0)),
+	reflect.TypeOf(int16(0)),
+	reflect.TypeOf(int32(0)),
+	reflect.TypeOf(int64(0)),
+	reflect.TypeOf(uint(0)),
+	reflect.TypeOf(uint8(0)),
+	reflect.TypeOf(uint16(0)),
+	reflect.TypeOf(uint32(0)),
+	reflect.TypeOf(uint64(0)),
+	reflect.TypeOf(float32(0)),
+	reflect.TypeOf(float64(0)),
+}
+
+// integerPrefixes are the literal prefixes recognized in integer values, and the base each implies
+var integerPrefixes = map[string]int{"0x": 16, "0o": 8, "0b": 2}
+
+// integerBase returns the digits of an integer value and the base to parse them in.  Values prefixed with
+// "0x", "0o" or "0b" are read in that base; other values are decimal unless the `base` hint says otherwise.
+// A prefix matching the hinted base is allowed, e.g. "0xff" with `base:"16"`.
+func integerBase(v string, parserHints Hints) (string, int, error) {
+	v = strings.TrimSpace(v)
+	sign, digits := "", v
+	if digits != "" && (digits[0] == '-' || digits[0] == '+') {
+		sign, digits = digits[:1], digits[1:]
+	}
+	prefixBase, hasPrefix := 0, false
+	if len(digits) > 2 {
+		prefixBase, hasPrefix = integerPrefixes[strings.ToLower(digits[:2])]
+	}
+
+	if !parserHints.Has("base") {
+		if hasPrefix {
+			// base 0 lets strconv handle the prefix, along with underscores between digits
+			return v, 0, nil
+		}
+		return v, 10, nil
+	}
+
+	base, err := numberBase(parserHints)
+	if err != nil {
+		return "", 0, err
+	}
+	if base > 36 {
+		return "", 0, fmt.Errorf("base parser hint for integers must be 0 or between 2 and 36, got %d", base)
+	}
+	if hasPrefix && base != 0 {
+		if prefixBase != base {
+			return "", 0, fmt.Errorf("%q has a base %d prefix, but base %d was requested", v, prefixBase, base)
+		}
+		return sign + digits[2:], base, nil
+	}
+	return v, base, nil
+}
+
+// parseNumber is a TargetParser for every integer and float width.  Values are range checked against
+// toType; integer types accept the `unit:"bytes"` hint and float types the `unit:"percent"` hint (see
+// ParsePercent).  Integers may be written with a 0x, 0o or 0b
+// prefix, or in the base given by the `base` hint.  As rune and byte are aliases of int32 and uint8,
+// those types accept the `rune:"true"` hint to parse a character instead of a number.
+func parseNumber(v string, toType reflect.Type, parserHints Hints) (any, error) {
+	zero := reflect.Zero(toType).Interface()
+	bits := toType.Bits()
+
+	if kind := toType.Kind(); kind == reflect.Int32 || kind == reflect.Uint8 {
+		isRune, err := parserHints.GetBool("rune")
+		if err != nil {
+			return zero, err
+		}
+		if isRune {
+			return parseChar(v, toType)
+		}
+	}
+
+	switch toType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bytes, err := unitIsBytes(parserHints)
+		if err != nil {
+			return zero, err
+		}
+		var n int64
+		if bytes {
+			size, err := ParseByteSize(v)
+			if err != nil {
+				return zero, err
+			}
+			n = int64(size)
+			if reflect.Zero(toType).OverflowInt(n) {
+				return zero, fmt.Errorf("byte size %q overflows %s", v, toType)
+			}
+		} else {
+			digits, base, err := integerBase(v, parserHints)
+			if err != nil {
+				return zero, err
+			}
+			n, err = strconv.ParseInt(digits, base, bits)
+			if err != nil {
+				return zero, err
+			}
+		}
+		return reflect.ValueOf(n).Convert(toType).Interface(), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		bytes, err := unitIsBytes(parserHints)
+		if err != nil {
+			return zero, err
+		}
+		var n uint64
+		if bytes {
+			size, err := ParseByteSize(v)
+			if err != nil {
+				return zero, err
+			}
+			n = uint64(size)
+			if reflect.Zero(toType).OverflowUint(n) {
+				return zero, fmt.Errorf("byte size %q overflows %s", v, toType)
+			}
+		} else {
+			digits, base, err := integerBase(v, parserHints)
+			if err != nil {
+				return zero, err
+			}
+			n, err = strconv.ParseUint(digits, base, bits)
+			if err != nil {
+				return zero, err
+			}
+		}
+		return reflect.ValueOf(n).Convert(toType).Interface(), nil
+
+	case reflect.Float32, reflect.Float64:
+		unit, err := parserHints.GetString("unit")
+		if err != nil {
+			return zero, err
+		}
+		var f float64
+		switch unit {
+		case "":
+			f, err = strconv.ParseFloat(strings.TrimSpace(v), bits)
+		case "percent":
+			f, err = ParsePercent(v)
+		default:
+			err = fmt.Errorf("unknown unit %q for %s", unit, toType)
+		}
+		if err != nil {
+			return zero, err
+		}
+		return reflect.ValueOf(f).Convert(toType).Interface(), nil
+	}
+
+	return zero, UnhandledParserTypeError{Msg: fmt.Sprintf("unknown type for parser: %v", toType)}
+}
+
+// numberBase reads the `base` hint shared by the arbitrary precision parsers, defaulting to 10.
+// A base of 0 infers the base from a "0x", "0o" or "0b" prefix.
+func numberBase(parserHints Hints) (int, error) {
+	if !parserHints.Has("base") {
+		return 10, nil
+	}
+	base, err := parserHints.GetInt("base")
+	if err != nil {
+		return 0, err
+	}
+	if base != 0 && (base < 2 || base > 62) {
+		return 0, fmt.Errorf("base parser hint must be 0 or between 2 and 62, got %d", base)
+	}
+	return base, nil
+}
+
+// parseBigInt handles *big.Int, with an optional `base:"16"` hint
+func parseBigInt(v string, parserHints Hints) (any, error) {
+	base, err := numberBase(parserHints)
+	if err != nil {
+		return (*big.Int)(nil), err
+	}
+	n, ok := new(big.Int).SetString(strings.TrimSpace(v), base)
+	if !ok {
+		return (*big.Int)(nil), fmt.Errorf("invalid base %d integer %q", base, v)
+	}
+	return n, nil
+}
+
+// parseBigFloat handles *big.Float.  The `base` hint accepts 0, 2, 8, 10 or 16, and a `precision:"256"` hint
+// sets the mantissa precision in bits; otherwise the precision is 64 bits.
+func parseBigFloat(v string, parserHints Hints) (any, error) {
+	base, err := numberBase(parserHints)
+	if err != nil {
+		return (*big.Float)(nil), err
+	}
+	switch base {
+	case 0, 2, 8, 10, 16:
+	default:
+		return (*big.Float)(nil), fmt.Errorf("base parser hint for floats must be 0, 2, 8, 10 or 16, got %d", base)
+	}
+
+	precision, err := parserHints.GetInt("precision")
+	if err != nil {
+		return (*big.Float)(nil), err
+	}
+	if precision < 0 || precision > big.MaxPrec {
+		return (*big.Float)(nil), fmt.Errorf("precision parser hint out of range: %d", precision)
+	}
+	if precision == 0 {
+		precision = 64
+	}
+
+	f, _, err := new(big.Float).SetPrec(uint(precision)).Parse(strings.TrimSpace(v), base)
+	if err != nil {
+		return (*big.Float)(nil), err
+	}
+	return f, nil
+}
+
+// parseBigRat handles *big.Rat from fractions ("3/4") or decimals ("0.75")
+func parseBigRat(v string, parserHints Hints) (any, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(v))
+	if !ok {
+		return (*big.Rat)(nil), fmt.Errorf("invalid rational %q", v)
+	}
+	return r, nil
+}
+
+// validDecimal checks that v is a plain decimal number, optionally signed, such as "19.99" or "-0.5", with at
+// most scale fractional digits when scale is non-negative
+func validDecimal(v string, scale int) error {
+	digits := strings.TrimLeft(v, "+-")
+	if len(v)-len(digits) > 1 {
+		return fmt.Errorf("invalid decimal %q", v)
+	}
+	whole, fraction, hasPoint := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" || hasPoint && fraction == "" {
+		return fmt.Errorf("invalid decimal %q", v)
+	}
+	for _, part := range []string{whole, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return fmt.Errorf("invalid decimal %q", v)
+			}
+		}
+	}
+	if scale >= 0 && len(fraction) > scale {
+		return fmt.Errorf("decimal %q has more than %d fractional digits", v, scale)
+	}
+	return nil
+}
+
+// RegisterDecimalParser registers construct as the parser for the decimal type T, so that money-like values
+// such as "19.99" reach the decimal implementation as text rather than passing through a float.  Values are
+// checked to be plain decimal numbers first, and the `scale:"2"` hint rejects values with more fractional
+// digits than the field allows.
+//
+//	patchpanel.RegisterDecimalParser(pp, decimal.NewFromString)
+//
+// Types that implement encoding.TextUnmarshaler can use the DecimalFromText adapter as construct.
+func RegisterDecimalParser[T any](pp *PatchPanel, construct func(string) (T, error)) {
+	AddTypedParser(pp, func(v string, parserHints Hints) (T, error) {
+		var zero T
+		scale := -1
+		if parserHints.Has("scale") {
+			var err error
+			scale, err = parserHints.GetInt("scale")
+			if err != nil {
+				return zero, err
+			}
+			if scale < 0 {
+				return zero, fmt.Errorf("scale parser hint must not be negative, got %d", scale)
+			}
+		}
+		v = strings.TrimSpace(v)
+		if err := validDecimal(v, scale); err != nil {
+			return zero, err
+		}
+		return construct(v)
+	})
+}
+
+// DecimalFromText adapts a decimal type whose pointer implements encoding.TextUnmarshaler into a constructor
+// for RegisterDecimalParser, e.g. RegisterDecimalParser(pp, DecimalFromText[Money]).
+func DecimalFromText[T any, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](v string) (T, error) {
+	var d T
+	err := PT(&d).UnmarshalText([]byte(v))
+	return d, err
+}
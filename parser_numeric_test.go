@@ -0,0 +1,327 @@
+package patchpanel
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ByteSize
+		wantErr bool
+	}{
+		{input: "1024", want: 1024},
+		{input: "512KiB", want: 512 * KiB},
+		{input: "10MB", want: 10 * MB},
+		{input: "10mb", want: 10 * MB},
+		{input: "1.5G", want: GiB + GiB/2},
+		{input: "2 GiB", want: 2 * GiB},
+		{input: "7B", want: 7},
+		{input: "0.5KB", want: 500},
+		{input: "8EiB", wantErr: true},
+		{input: "-1K", wantErr: true},
+		{input: "12 parsecs", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "KiB", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{size: 0, want: "0B"},
+		{size: 512 * KiB, want: "512KiB"},
+		{size: 3 * GiB, want: "3GiB"},
+		{size: 1000, want: "1000B"},
+	}
+	for _, tt := range tests {
+		if got := tt.size.String(); got != tt.want {
+			t.Errorf("ByteSize(%d).String() = %v, want %v", int64(tt.size), got, tt.want)
+		}
+	}
+}
+
+type SizeStruct struct {
+	Buffer   ByteSize `default:"64KiB"`
+	Limit    int64    `default:"10MB" unit:"bytes"`
+	LimitInt int      `default:"1K" unit:"bytes"`
+	Plain    int64    `default:"10"`
+	BadUnit  int64    `default:"10" unit:"furlongs"`
+	NotBytes int64    `default:"10MB"`
+}
+
+func Test_byteSizeParsers(t *testing.T) {
+	pp := New()
+	ss := ToReflectType(SizeStruct{})
+	hints := []string{"unit"}
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantErr   bool
+	}{
+		{name: "byte size type", fieldName: "Buffer", want: 64 * KiB},
+		{name: "int64 with unit hint", fieldName: "Limit", want: int64(10 * MB)},
+		{name: "int with unit hint", fieldName: "LimitInt", want: 1024},
+		{name: "int64 without hint", fieldName: "Plain", want: int64(10)},
+		{name: "unknown unit", fieldName: "BadUnit", wantErr: true},
+		{name: "suffix without hint", fieldName: "NotBytes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ss, hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetDefault() got = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+type NumberStruct struct {
+	Small    int8    `default:"-12"`
+	Overflow int8    `default:"300"`
+	Port     uint16  `default:"8080"`
+	Negative uint    `default:"-1"`
+	Ratio    float64 `default:"0.75"`
+	Single   float32 `default:"1.5"`
+	Buffer   uint32  `default:"4KiB" unit:"bytes"`
+	TooBig   uint8   `default:"1KiB" unit:"bytes"`
+	Hex      uint32  `default:"0x1F"`
+	Octal    uint16  `default:"0o755"`
+	Binary   uint8   `default:"0b1010"`
+	NegHex   int16   `default:"-0x10"`
+	Leading  int     `default:"010"`
+	HexHint  uint64  `default:"ff" base:"16"`
+	HexBoth  uint64  `default:"0xFF" base:"16"`
+	Mismatch uint64  `default:"0b11" base:"16"`
+	Infer    int     `default:"0755" base:"0"`
+	BadBase  int     `default:"10" base:"40"`
+	Sampling float64 `default:"85%" unit:"percent"`
+	Fraction float32 `default:"0.25" unit:"percent"`
+	TooMuch  float64 `default:"150%" unit:"percent"`
+	Deficit  float64 `default:"-0.1" unit:"percent"`
+	NotANum  float64 `default:"NaN" unit:"percent"`
+	BadUnit  float64 `default:"1" unit:"bytes"`
+}
+
+func Test_numberParser(t *testing.T) {
+	pp := New()
+	ns := ToReflectType(NumberStruct{})
+	hints := []string{"unit", "base"}
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantErr   bool
+	}{
+		{name: "int8", fieldName: "Small", want: int8(-12)},
+		{name: "int8 overflow", fieldName: "Overflow", wantErr: true},
+		{name: "uint16", fieldName: "Port", want: uint16(8080)},
+		{name: "negative uint", fieldName: "Negative", wantErr: true},
+		{name: "float64", fieldName: "Ratio", want: 0.75},
+		{name: "float32", fieldName: "Single", want: float32(1.5)},
+		{name: "uint32 bytes", fieldName: "Buffer", want: uint32(4096)},
+		{name: "uint8 bytes overflow", fieldName: "TooBig", wantErr: true},
+		{name: "hex prefix", fieldName: "Hex", want: uint32(31)},
+		{name: "octal prefix", fieldName: "Octal", want: uint16(0o755)},
+		{name: "binary prefix", fieldName: "Binary", want: uint8(10)},
+		{name: "negative hex", fieldName: "NegHex", want: int16(-16)},
+		{name: "leading zero stays decimal", fieldName: "Leading", want: 10},
+		{name: "base hint", fieldName: "HexHint", want: uint64(255)},
+		{name: "base hint with prefix", fieldName: "HexBoth", want: uint64(255)},
+		{name: "prefix contradicts base", fieldName: "Mismatch", wantErr: true},
+		{name: "inferred base", fieldName: "Infer", want: 0o755},
+		{name: "base out of range", fieldName: "BadBase", wantErr: true},
+		{name: "percent", fieldName: "Sampling", want: 0.85},
+		{name: "percent as fraction", fieldName: "Fraction", want: float32(0.25)},
+		{name: "percent over 100", fieldName: "TooMuch", wantErr: true},
+		{name: "negative percent", fieldName: "Deficit", wantErr: true},
+		{name: "NaN percent", fieldName: "NotANum", wantErr: true},
+		{name: "unknown float unit", fieldName: "BadUnit", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ns, hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetDefault() got = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+type Celsius float64
+type Fahrenheit float64
+
+func TestPatchPanel_AddTargetParser(t *testing.T) {
+	pp := New()
+
+	var seen []reflect.Type
+	pp.AddTargetParser(func(value string, toType reflect.Type, parserHints Hints) (any, error) {
+		seen = append(seen, toType)
+		f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSuffix(value, "C"), "F"), 64)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(f).Convert(toType).Interface(), nil
+	}, reflect.TypeOf(Celsius(0)), reflect.TypeOf(Fahrenheit(0)))
+
+	var dst struct {
+		Indoor  Celsius    `default:"21C"`
+		Outdoor Fahrenheit `default:"50F"`
+	}
+	if err := pp.Populate(&dst); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if dst.Indoor != 21 || dst.Outdoor != 50 {
+		t.Errorf("Populate() got = %+v", dst)
+	}
+	if len(seen) != 2 || seen[0] != reflect.TypeOf(Celsius(0)) || seen[1] != reflect.TypeOf(Fahrenheit(0)) {
+		t.Errorf("AddTargetParser() parser saw types %v", seen)
+	}
+}
+
+type BigStruct struct {
+	Modulus   *big.Int   `default:"340282366920938463463374607431768211457"`
+	Generator *big.Int   `default:"ff" base:"16"`
+	Prefixed  *big.Int   `default:"0x1F" base:"0"`
+	NotHex    *big.Int   `default:"zz" base:"16"`
+	BadBase   *big.Int   `default:"1" base:"99"`
+	Threshold *big.Float `default:"0.1000000000000000000001" precision:"128"`
+	HexFloat  *big.Float `default:"0x1p-2" base:"0"`
+	OddBase   *big.Float `default:"1" base:"3"`
+	Fraction  *big.Rat   `default:"3/4"`
+	Decimal   *big.Rat   `default:"0.75"`
+	DivByZero *big.Rat   `default:"1/0"`
+}
+
+func Test_bigParsers(t *testing.T) {
+	pp := New()
+	bs := ToReflectType(BigStruct{})
+	hints := []string{"base", "precision"}
+
+	modulus, _ := new(big.Int).SetString("340282366920938463463374607431768211457", 10)
+	threshold, _, _ := new(big.Float).SetPrec(128).Parse("0.1000000000000000000001", 10)
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      string
+		wantErr   bool
+	}{
+		{name: "big int", fieldName: "Modulus", want: modulus.String()},
+		{name: "big int base 16", fieldName: "Generator", want: "255"},
+		{name: "big int prefixed", fieldName: "Prefixed", want: "31"},
+		{name: "big int invalid digits", fieldName: "NotHex", wantErr: true},
+		{name: "big int invalid base", fieldName: "BadBase", wantErr: true},
+		{name: "big float precision", fieldName: "Threshold", want: threshold.Text('g', 30)},
+		{name: "big float hex", fieldName: "HexFloat", want: "0.25"},
+		{name: "big float unsupported base", fieldName: "OddBase", wantErr: true},
+		{name: "big rat fraction", fieldName: "Fraction", want: "3/4"},
+		{name: "big rat decimal", fieldName: "Decimal", want: "3/4"},
+		{name: "big rat zero denominator", fieldName: "DivByZero", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, bs, hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var gotStr string
+			switch n := got.(type) {
+			case *big.Int:
+				gotStr = n.String()
+			case *big.Float:
+				gotStr = n.Text('g', 30)
+			case *big.Rat:
+				gotStr = n.String()
+			}
+			if gotStr != tt.want {
+				t.Errorf("GetDefault() got = %v, want %v", gotStr, tt.want)
+			}
+		})
+	}
+}
+
+// Cents is a fixed-point amount used to exercise the decimal parser hooks
+type Cents int64
+
+func (c *Cents) UnmarshalText(text []byte) error {
+	whole, fraction, _ := strings.Cut(string(text), ".")
+	fraction = (fraction + "00")[:2]
+	n, err := strconv.ParseInt(whole+fraction, 10, 64)
+	*c = Cents(n)
+	return err
+}
+
+type DecimalStruct struct {
+	Price    Cents `default:"19.99" scale:"2"`
+	Whole    Cents `default:"5" scale:"2"`
+	Precise  Cents `default:"19.999" scale:"2"`
+	Exponent Cents `default:"1e3"`
+	Empty    Cents `default:"."`
+	Signs    Cents `default:"--1"`
+}
+
+func TestRegisterDecimalParser(t *testing.T) {
+	pp := New()
+	RegisterDecimalParser(pp, DecimalFromText[Cents])
+	ds := ToReflectType(DecimalStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      Cents
+		wantErr   bool
+	}{
+		{name: "decimal", fieldName: "Price", want: 1999},
+		{name: "whole number", fieldName: "Whole", want: 500},
+		{name: "too many fractional digits", fieldName: "Precise", wantErr: true},
+		{name: "exponent", fieldName: "Exponent", wantErr: true},
+		{name: "no digits", fieldName: "Empty", wantErr: true},
+		{name: "double sign", fieldName: "Signs", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ds, []string{"scale"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
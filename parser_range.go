@@ -0,0 +1,98 @@
+package patchpanel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Number is the set of types a Range may be built from
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Range is an inclusive range of numbers, parsed from values such as "8000-8100" or "1..5".  A single number is a
+// range of one value.
+type Range[T Number] struct {
+	Low  T
+	High T
+}
+
+// Contains reports whether v is within the range, inclusively
+func (r Range[T]) Contains(v T) bool {
+	return r.Low <= v && v <= r.High
+}
+
+func (r Range[T]) String() string {
+	return fmt.Sprintf("%v-%v", r.Low, r.High)
+}
+
+// splitRange splits "low-high" or "low..high" into its bounds.  A leading minus sign belongs to the low bound,
+// so "-10-10" and "-10..10" are both -10 through 10.  When exponents is set, as for floats, a minus following an
+// exponent marker (e or E, or p or P after a 0x prefix) belongs to the exponent; otherwise e and E are digits, so
+// "0x1e-0x20" is 0x1e through 0x20.
+func splitRange(v string, exponents bool) (string, string, bool) {
+	if low, high, ok := strings.Cut(v, ".."); ok {
+		return low, high, true
+	}
+	for i := 1; i < len(v); i++ {
+		// a minus following the low bound's digits, rather than a sign or an exponent
+		if v[i] != '-' || v[i-1] == '-' || (exponents && endsInExponent(v[:i])) {
+			continue
+		}
+		return v[:i], v[i+1:], true
+	}
+	return v, v, false
+}
+
+// endsInExponent reports whether the float prefix ends in an exponent marker
+func endsInExponent(prefix string) bool {
+	last := prefix[len(prefix)-1]
+	digits := strings.ToLower(strings.TrimLeft(strings.TrimSpace(prefix), "+-"))
+	if strings.HasPrefix(digits, "0x") {
+		return last == 'p' || last == 'P'
+	}
+	return last == 'e' || last == 'E'
+}
+
+// ParseRange parses an inclusive range of T, e.g. "8000-8100" or "1..5", requiring low <= high.  Bounds are parsed
+// as numbers of type T are, so parserHints such as `base:"16"` apply to both.
+func ParseRange[T Number](v string, parserHints Hints) (Range[T], error) {
+	typ := reflect.TypeFor[T]()
+	// bounds written in another base have no exponents
+	exponents := (typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64) && !parserHints.Has("base")
+	low, high, _ := splitRange(strings.TrimSpace(v), exponents)
+
+	lowVal, err := parseNumber(strings.TrimSpace(low), typ, parserHints)
+	if err != nil {
+		return Range[T]{}, fmt.Errorf("invalid range %q: %w", v, err)
+	}
+	highVal, err := parseNumber(strings.TrimSpace(high), typ, parserHints)
+	if err != nil {
+		return Range[T]{}, fmt.Errorf("invalid range %q: %w", v, err)
+	}
+
+	r := Range[T]{Low: lowVal.(T), High: highVal.(T)}
+	if r.Low > r.High {
+		return Range[T]{}, fmt.Errorf("invalid range %q: low bound is greater than high bound", v)
+	}
+	return r, nil
+}
+
+// RegisterRange registers the parser for Range[T].  Ranges of int, int64, uint16, uint32, uint64 and float64 are
+// registered by New; other number types, including named ones, need registering.
+func RegisterRange[T Number](pp *PatchPanel) {
+	AddTypedParser(pp, ParseRange[T])
+}
+
+// registerRanges registers the built-in Range instantiations
+func registerRanges(pp *PatchPanel) {
+	RegisterRange[int](pp)
+	RegisterRange[int64](pp)
+	RegisterRange[uint16](pp)
+	RegisterRange[uint32](pp)
+	RegisterRange[uint64](pp)
+	RegisterRange[float64](pp)
+}
@@ -0,0 +1,93 @@
+package patchpanel
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Shard uint8
+
+type RangeStruct struct {
+	Ports     Range[uint16]  `default:"8000-8100"`
+	Dots      Range[int]     `default:"1..5"`
+	Single    Range[int]     `default:"7"`
+	Negative  Range[int64]   `default:"-10-10"`
+	NegDots   Range[int64]   `default:"-10..-5"`
+	Spaces    Range[int]     `default:" 1 - 3 "`
+	Floats    Range[float64] `default:"0.5..1.5"`
+	Exponent  Range[float64] `default:"1e-3-1e3"`
+	Hex       Range[uint32]  `default:"0x10-0x1F"`
+	HexE      Range[int]     `default:"0x1e-0x20"`
+	BaseHex   Range[int]     `default:"1e-20" base:"16"`
+	HexFloat  Range[float64] `default:"0x1p-2-0x1p3"`
+	Inverted  Range[int]     `default:"5-1"`
+	Overflow  Range[uint16]  `default:"1-70000"`
+	Garbage   Range[int]     `default:"a-b"`
+	Shards    Range[Shard]   `default:"0..15"`
+	Unhandled Range[int8]    `default:"1-2"`
+}
+
+func Test_rangeParser(t *testing.T) {
+	pp := New()
+	RegisterRange[Shard](pp)
+	rs := ToReflectType(RangeStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantErr   bool
+	}{
+		{name: "dash", fieldName: "Ports", want: Range[uint16]{8000, 8100}},
+		{name: "dots", fieldName: "Dots", want: Range[int]{1, 5}},
+		{name: "single value", fieldName: "Single", want: Range[int]{7, 7}},
+		{name: "negative low", fieldName: "Negative", want: Range[int64]{-10, 10}},
+		{name: "negative dots", fieldName: "NegDots", want: Range[int64]{-10, -5}},
+		{name: "spaces", fieldName: "Spaces", want: Range[int]{1, 3}},
+		{name: "floats", fieldName: "Floats", want: Range[float64]{0.5, 1.5}},
+		{name: "exponents", fieldName: "Exponent", want: Range[float64]{0.001, 1000}},
+		{name: "hex", fieldName: "Hex", want: Range[uint32]{16, 31}},
+		{name: "hex with an e digit", fieldName: "HexE", want: Range[int]{30, 32}},
+		{name: "base hint with an e digit", fieldName: "BaseHex", want: Range[int]{30, 32}},
+		{name: "hex float exponents", fieldName: "HexFloat", want: Range[float64]{0.25, 8}},
+		{name: "low above high", fieldName: "Inverted", wantErr: true},
+		{name: "bound overflow", fieldName: "Overflow", wantErr: true},
+		{name: "not numbers", fieldName: "Garbage", wantErr: true},
+		{name: "registered named type", fieldName: "Shards", want: Range[Shard]{0, 15}},
+		{name: "unregistered instantiation", fieldName: "Unhandled", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, rs, []string{"base"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRange_Contains(t *testing.T) {
+	r := Range[uint16]{Low: 8000, High: 8100}
+	if !r.Contains(8000) || !r.Contains(8100) || r.Contains(7999) || r.Contains(8101) {
+		t.Errorf("Range.Contains() incorrect for %v", r)
+	}
+	if r.String() != "8000-8100" {
+		t.Errorf("Range.String() = %q", r.String())
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	if got, err := ParseRange[int]("0x1e-0x20", nil); err != nil || got != (Range[int]{30, 32}) {
+		t.Errorf("ParseRange(0x1e-0x20) = %v, %v", got, err)
+	}
+	if got, err := ParseRange[int]("1e-20", Hints{"base": "16"}); err != nil || got != (Range[int]{30, 32}) {
+		t.Errorf("ParseRange(1e-20, base 16) = %v, %v", got, err)
+	}
+	if got, err := ParseRange[float64]("-1e-3--1e-6", nil); err != nil || got != (Range[float64]{-0.001, -0.000001}) {
+		t.Errorf("ParseRange(-1e-3--1e-6) = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,211 @@
+package patchpanel
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SemVer is a semantic version (https://semver.org), e.g. "1.2.3-rc.1+build.5".  A leading "v" is accepted.
+type SemVer struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+	Build      string
+}
+
+// String renders the version without a leading "v"
+func (sv SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", sv.Major, sv.Minor, sv.Patch)
+	if sv.Prerelease != "" {
+		s += "-" + sv.Prerelease
+	}
+	if sv.Build != "" {
+		s += "+" + sv.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as sv has lower, equal or higher precedence than other.
+// Build metadata does not affect precedence.
+func (sv SemVer) Compare(other SemVer) int {
+	for _, pair := range [][2]uint64{{sv.Major, other.Major}, {sv.Minor, other.Minor}, {sv.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// a version without a prerelease has higher precedence than one with
+	switch {
+	case sv.Prerelease == other.Prerelease:
+		return 0
+	case sv.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+
+	a, b := strings.Split(sv.Prerelease, "."), strings.Split(other.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePrereleaseIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// comparePrereleaseIdentifier orders numeric identifiers numerically and below alphanumeric ones
+func comparePrereleaseIdentifier(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if an < bn {
+			return -1
+		} else if an > bn {
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// parseVersionNumber reads a numeric version component, rejecting leading zeros
+func parseVersionNumber(v string, component string) (uint64, error) {
+	if v == "" || (len(v) > 1 && v[0] == '0') {
+		return 0, fmt.Errorf("invalid %s version %q", component, v)
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s version %q", component, v)
+	}
+	return n, nil
+}
+
+// validIdentifiers checks dot separated prerelease or build identifiers
+func validIdentifiers(v string) bool {
+	for _, identifier := range strings.Split(v, ".") {
+		if identifier == "" {
+			return false
+		}
+		for _, r := range identifier {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseSemVer reads a version, allowing minor and patch to be omitted when partial is set
+// (as they may be in constraints, e.g. ">=1.2")
+func parseSemVer(v string, partial bool) (SemVer, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(v), "v")
+
+	var sv SemVer
+	s, build, hasBuild := strings.Cut(s, "+")
+	s, prerelease, hasPrerelease := strings.Cut(s, "-")
+	if hasBuild && !validIdentifiers(build) {
+		return SemVer{}, fmt.Errorf("invalid build metadata in %q", v)
+	}
+	if hasPrerelease && !validIdentifiers(prerelease) {
+		return SemVer{}, fmt.Errorf("invalid prerelease in %q", v)
+	}
+	sv.Build, sv.Prerelease = build, prerelease
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 || (!partial && len(parts) != 3) {
+		return SemVer{}, fmt.Errorf("invalid semantic version %q, expected MAJOR.MINOR.PATCH", v)
+	}
+	numbers := []*uint64{&sv.Major, &sv.Minor, &sv.Patch}
+	for i, part := range parts {
+		n, err := parseVersionNumber(part, []string{"major", "minor", "patch"}[i])
+		if err != nil {
+			return SemVer{}, fmt.Errorf("%w in %q", err, v)
+		}
+		*numbers[i] = n
+	}
+	return sv, nil
+}
+
+// ParseSemVer reads a full MAJOR.MINOR.PATCH version with optional prerelease and build metadata
+func ParseSemVer(v string) (SemVer, error) {
+	return parseSemVer(v, false)
+}
+
+// Satisfies reports whether the version meets every comparison in constraint, e.g. ">=1.2, <2".
+// Supported operators are =, !=, >, >=, < and <=; a bare version means =.  Versions in a constraint may omit
+// the minor and patch numbers, which are then zero.
+func (sv SemVer) Satisfies(constraint string) (bool, error) {
+	clauses := strings.FieldsFunc(constraint, func(r rune) bool { return r == ',' })
+	if len(clauses) == 0 {
+		return false, errors.New("empty version constraint")
+	}
+	for _, clause := range clauses {
+		clause = strings.TrimSpace(clause)
+		op := strings.TrimRight(clause[:len(clause)-len(strings.TrimLeft(clause, "<>=!"))], " ")
+		want, err := parseSemVer(strings.TrimSpace(clause[len(op):]), true)
+		if err != nil {
+			return false, fmt.Errorf("invalid version constraint %q: %w", clause, err)
+		}
+		c := sv.Compare(want)
+		var ok bool
+		switch op {
+		case "", "=", "==":
+			ok = c == 0
+		case "!=":
+			ok = c != 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		default:
+			return false, fmt.Errorf("unknown operator %q in version constraint %q", op, clause)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parseSemVerHinted handles SemVer values, enforcing the optional `constraint:">=1.2"` hint
+func parseSemVerHinted(v string, parserHints Hints) (any, error) {
+	sv, err := ParseSemVer(v)
+	if err != nil {
+		return SemVer{}, err
+	}
+	if parserHints.Has("constraint") {
+		constraint, err := parserHints.GetString("constraint")
+		if err != nil {
+			return SemVer{}, err
+		}
+		ok, err := sv.Satisfies(constraint)
+		if err != nil {
+			return SemVer{}, err
+		}
+		if !ok {
+			return SemVer{}, fmt.Errorf("version %s does not satisfy %q", sv, constraint)
+		}
+	}
+	return sv, nil
+}
@@ -0,0 +1,122 @@
+package patchpanel
+
+import (
+	"testing"
+)
+
+func TestParseSemVer(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    SemVer
+		wantErr bool
+	}{
+		{input: "1.2.3", want: SemVer{Major: 1, Minor: 2, Patch: 3}},
+		{input: "v1.2.3", want: SemVer{Major: 1, Minor: 2, Patch: 3}},
+		{input: "1.2.3-rc.1", want: SemVer{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}},
+		{input: "1.2.3-rc.1+build.5", want: SemVer{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1", Build: "build.5"}},
+		{input: "1.2.3+20240601", want: SemVer{Major: 1, Minor: 2, Patch: 3, Build: "20240601"}},
+		{input: "1.2", wantErr: true},
+		{input: "1.02.3", wantErr: true},
+		{input: "1.2.3-", wantErr: true},
+		{input: "1.2.3-rc..1", wantErr: true},
+		{input: "1.2.3+bad_meta", wantErr: true},
+		{input: "one.two.three", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSemVer(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSemVer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseSemVer() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSemVer_Compare(t *testing.T) {
+	// ordered by increasing precedence, per the semver specification's example
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		lower, _ := ParseSemVer(ordered[i])
+		higher, _ := ParseSemVer(ordered[i+1])
+		if lower.Compare(higher) != -1 || higher.Compare(lower) != 1 {
+			t.Errorf("Compare() expected %s < %s", lower, higher)
+		}
+	}
+	a, _ := ParseSemVer("1.0.0+a")
+	b, _ := ParseSemVer("1.0.0+b")
+	if a.Compare(b) != 0 {
+		t.Errorf("Compare() build metadata should not affect precedence")
+	}
+}
+
+func TestSemVer_Satisfies(t *testing.T) {
+	v, _ := ParseSemVer("1.4.2")
+	tests := []struct {
+		constraint string
+		want       bool
+		wantErr    bool
+	}{
+		{constraint: ">=1.2", want: true},
+		{constraint: ">=1.2, <2", want: true},
+		{constraint: ">=1.5", want: false},
+		{constraint: "1.4.2", want: true},
+		{constraint: "=1.4.2", want: true},
+		{constraint: "!=1.4.2", want: false},
+		{constraint: "> 1.4.1,<= 1.4.2", want: true},
+		{constraint: "~>1.4", wantErr: true},
+		{constraint: ">=one", wantErr: true},
+		{constraint: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := v.Satisfies(tt.constraint)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Satisfies() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Satisfies() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type SemVerStruct struct {
+	MinPeer   SemVer `default:"1.4.0" constraint:">=1.2"`
+	TooOld    SemVer `default:"1.1.9" constraint:">=1.2"`
+	Unchecked SemVer `default:"0.1.0-alpha"`
+}
+
+func Test_semVerParser(t *testing.T) {
+	pp := New()
+	ss := ToReflectType(SemVerStruct{})
+
+	tests := []struct {
+		fieldName string
+		want      SemVer
+		wantErr   bool
+	}{
+		{fieldName: "MinPeer", want: SemVer{Major: 1, Minor: 4}},
+		{fieldName: "TooOld", wantErr: true},
+		{fieldName: "Unchecked", want: SemVer{Minor: 1, Prerelease: "alpha"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fieldName, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ss, []string{"constraint"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package patchpanel
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// separator is the element separator for list values: the `sep` hint when provided, otherwise the
+// panel's TokenSeparator.  e.g. `default:"1s,5s,30s" sep:","`
+func (pc *PatchPanel) separator(parserHints Hints) string {
+	if sep, err := parserHints.GetString("sep"); err == nil && sep != "" {
+		return sep
+	}
+	return pc.tokenSeparator
+}
+
+// sliceParser derives a parser for a slice type whose element type has a parser, so that []int,
+// []time.Duration, []MyType, etc. don't need registering individually.  Entries are split with separator,
+// trimmed, and empty entries are dropped; each entry is parsed with the element's parser and hints.
+func (pc *PatchPanel) sliceParser(sliceType reflect.Type, elemParser HintsParser) HintsParser {
+	return func(v string, parserHints Hints) (any, error) {
+		tokens := splitTokens(v, pc.separator(parserHints))
+		out := reflect.MakeSlice(sliceType, len(tokens), len(tokens))
+		for i, token := range tokens {
+			val, err := elemParser(token, parserHints)
+			if err == nil {
+				err = assign(out.Index(i), val)
+			}
+			if err != nil {
+				return reflect.Zero(sliceType).Interface(), fmt.Errorf("element %d (%q): %w", i, token, err)
+			}
+		}
+		return out.Interface(), nil
+	}
+}
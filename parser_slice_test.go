@@ -0,0 +1,84 @@
+package patchpanel
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type SliceStruct struct {
+	Ints       []int           `default:"1·2·3"`
+	Durations  []time.Duration `default:"1s, 5s, 30s" sep:","`
+	Uints      []uint16        `default:"1|2|70000" sep:"|"`
+	Ports      []Port          `default:"80·443"`
+	Empty      []string        `default:""`
+	Prefixes   []netip.Prefix  `default:"10.0.0.0/8;192.168.0.0/16" sep:";"`
+	Weekdays   WeekdaySet      `default:"mon,fri" sep:","`
+	Unhandled  []chan int      `default:"1"`
+	ByteSlices [][]byte        `default:"aGk=·eW8=" encoding:"base64"`
+}
+
+func Test_sliceParser(t *testing.T) {
+	pp := New()
+	AddTypedParser(pp, func(v string, hints Hints) (Port, error) {
+		n, err := parseNumber(v, reflect.TypeOf(0), hints)
+		if err != nil {
+			return 0, err
+		}
+		return Port(n.(int)), nil
+	})
+	ss := ToReflectType(SliceStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		hints     []string
+		want      any
+		wantErr   bool
+	}{
+		{name: "ints", fieldName: "Ints", want: []int{1, 2, 3}},
+		{name: "sep hint", fieldName: "Durations", hints: []string{"sep"}, want: []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}},
+		{name: "element out of range", fieldName: "Uints", hints: []string{"sep"}, wantErr: true},
+		{name: "custom element type", fieldName: "Ports", want: []Port{80, 443}},
+		{name: "registered slice honors sep", fieldName: "Prefixes", hints: []string{"sep"}, want: []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16"),
+		}},
+		{name: "weekday set honors sep", fieldName: "Weekdays", hints: []string{"sep"}, want: WeekdaySet(1<<time.Monday | 1<<time.Friday)},
+		{name: "element hints", fieldName: "ByteSlices", hints: []string{"encoding"}, want: [][]byte{[]byte("hi"), []byte("yo")}},
+		{name: "no element parser", fieldName: "Unhandled", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ss, tt.hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchPanel_PopulateSlices(t *testing.T) {
+	pp := New()
+	var dst struct {
+		Retries []int           `default:"1,2,4" sep:","`
+		Backoff []time.Duration `default:"100ms·1s"`
+		Empty   []string        `default:""`
+	}
+	if err := pp.Populate(&dst); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if !reflect.DeepEqual(dst.Retries, []int{1, 2, 4}) {
+		t.Errorf("Populate() Retries = %v", dst.Retries)
+	}
+	if !reflect.DeepEqual(dst.Backoff, []time.Duration{100 * time.Millisecond, time.Second}) {
+		t.Errorf("Populate() Backoff = %v", dst.Backoff)
+	}
+	if dst.Empty == nil || len(dst.Empty) != 0 {
+		t.Errorf("Populate() Empty = %#v, want an empty slice", dst.Empty)
+	}
+}
@@ -295,3 +295,107 @@ func Test_getFieldTag(t *testing.T) {
 		})
 	}
 }
+
+type MultiTagStruct struct {
+	Workers int           `default:"8" min:"1" max:"0x40"`
+	Timeout time.Duration `default:"5s" max:"forever"`
+	Label   string
+}
+
+func TestPatchPanel_GetFieldTags(t *testing.T) {
+	pp := New()
+	mt := ToReflectType(MultiTagStruct{})
+	tagNames := []string{"default", "min", "max"}
+
+	tests := []struct {
+		name      string
+		fieldName string
+		t         reflect.Type
+		want      map[string]any
+		wantErr   bool
+	}{
+		{name: "all tags coerced", fieldName: "Workers", t: mt, want: map[string]any{"default": 8, "min": 1, "max": 64}},
+		{name: "missing tags skipped", fieldName: "Label", t: mt, want: map[string]any{}},
+		{name: "coercion failure keeps earlier tags", fieldName: "Timeout", t: mt, want: map[string]any{"default": 5 * time.Second}, wantErr: true},
+		{name: "no such field", fieldName: "Missing", t: mt, wantErr: true},
+		{name: "not a struct", fieldName: "Workers", t: reflect.TypeOf(1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetFieldTags(tt.fieldName, tagNames, tt.t, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFieldTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFieldTags() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllTags(t *testing.T) {
+	type tagged struct {
+		Port     int    `default:"8080" min:"1024"`
+		Name     string `default:"app" json:"name,omitempty"`
+		Untagged bool
+	}
+
+	wantTags := map[string]reflect.StructTag{
+		"Port": `default:"8080" min:"1024"`,
+		"Name": `default:"app" json:"name,omitempty"`,
+	}
+	if got := AllTags(reflect.TypeOf(&tagged{})); !reflect.DeepEqual(got, wantTags) {
+		t.Errorf("AllTags() = %v, want %v", got, wantTags)
+	}
+
+	wantValues := map[string]map[string]string{
+		"Port": {"default": "8080", "min": "1024"},
+		"Name": {"default": "app", "json": "name,omitempty"},
+	}
+	if got := AllTagValues(reflect.TypeOf(tagged{})); !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("AllTagValues() = %v, want %v", got, wantValues)
+	}
+
+	if got := AllTags(reflect.TypeOf(1)); got != nil {
+		t.Errorf("AllTags(int) = %v, want nil", got)
+	}
+	if got := AllTagValues(nil); got != nil {
+		t.Errorf("AllTagValues(nil) = %v, want nil", got)
+	}
+}
+
+type Level int
+
+func TestAddTypedParser(t *testing.T) {
+	pp := NewPatchPanel(TokenSeparator, KeyValueSeparator)
+
+	AddTypedParser(pp, func(value string, hints Hints) (Level, error) {
+		switch value {
+		case "low":
+			return 1, nil
+		case "high":
+			return 10, nil
+		}
+		return 0, errors.New("unknown level")
+	})
+
+	var dst struct {
+		Low   Level `default:"low"`
+		High  Level `default:"high"`
+		Other Level `default:"medium"`
+	}
+	err := pp.Populate(&dst)
+	if dst.Low != 1 || dst.High != 10 {
+		t.Errorf("Populate() got = %+v", dst)
+	}
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Other" {
+		t.Errorf("Populate() error = %v, want FieldError for Other", err)
+	}
+
+	// the registered reflect.Type is derived from the type parameter
+	if _, ok := pp.parser(reflect.TypeOf(Level(0))); !ok {
+		t.Errorf("AddTypedParser() did not register reflect.TypeOf(Level(0))")
+	}
+}
@@ -0,0 +1,160 @@
+package patchpanel
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"unicode/utf8"
+)
+
+// parseRegexp compiles a *regexp.Regexp.  The `regexpMode:"posix"` hint selects regexp.CompilePOSIX
+// (leftmost-longest matching, POSIX ERE syntax); the default is RE2 syntax via regexp.Compile.
+func parseRegexp(v string, parserHints Hints) (any, error) {
+	mode, err := parserHints.GetString("regexpMode")
+	if err != nil {
+		return (*regexp.Regexp)(nil), err
+	}
+
+	compile := regexp.Compile
+	if mode != "" {
+		switch mode {
+		case "posix":
+			compile = regexp.CompilePOSIX
+		case "re2":
+		default:
+			return (*regexp.Regexp)(nil), fmt.Errorf("unknown regexpMode %q, expected posix or re2", mode)
+		}
+	}
+
+	re, err := compile(v)
+	if err != nil {
+		return (*regexp.Regexp)(nil), err
+	}
+	return re, nil
+}
+
+// parseBytes handles []byte fields.  The `encoding` hint selects how the value is decoded:
+//
+//	encoding:"base64"     standard alphabet, padding required
+//	encoding:"base64url"  URL-safe alphabet, padding required
+//	encoding:"hex"
+//
+// Without the hint, the value's bytes are used as is.
+func parseBytes(v string, parserHints Hints) (any, error) {
+	encoding, err := parserHints.GetString("encoding")
+	if err != nil {
+		return []byte(nil), err
+	}
+	if encoding == "" {
+		return []byte(v), nil
+	}
+
+	var decoded []byte
+	switch encoding {
+	case "base64":
+		decoded, err = base64.StdEncoding.Strict().DecodeString(strings.TrimSpace(v))
+	case "base64url":
+		decoded, err = base64.URLEncoding.Strict().DecodeString(strings.TrimSpace(v))
+	case "hex":
+		decoded, err = hex.DecodeString(strings.TrimSpace(v))
+	default:
+		return []byte(nil), fmt.Errorf("unknown encoding %q, expected base64, base64url or hex", encoding)
+	}
+	if err != nil {
+		return []byte(nil), fmt.Errorf("invalid %s value: %w", encoding, err)
+	}
+	return decoded, nil
+}
+
+// parseRawMessage carries a JSON document through as-is after checking that it is well-formed,
+// leaving its structure to be decoded by the application.
+func parseRawMessage(v string, parserHints Hints) (any, error) {
+	raw := []byte(strings.TrimSpace(v))
+	if !json.Valid(raw) {
+		return json.RawMessage(nil), errors.New("value is not well-formed JSON")
+	}
+	return json.RawMessage(raw), nil
+}
+
+// ParseRune reads a single character, or a Go escape sequence such as "\t", "\u00a0" or "\x1f".
+// Whitespace is significant, so " " is a space.
+func ParseRune(v string) (rune, error) {
+	if strings.HasPrefix(v, "\\") {
+		r, _, tail, err := strconv.UnquoteChar(v, 0)
+		if err != nil {
+			return 0, fmt.Errorf("invalid escape sequence %q", v)
+		}
+		if tail != "" {
+			return 0, fmt.Errorf("expected a single character, got %q", v)
+		}
+		return r, nil
+	}
+
+	r, size := utf8.DecodeRuneInString(v)
+	if size == 0 {
+		return 0, errors.New("expected a single character, got an empty value")
+	}
+	if r == utf8.RuneError && size == 1 {
+		return 0, fmt.Errorf("invalid UTF-8 in %q", v)
+	}
+	if size != len(v) {
+		return 0, fmt.Errorf("expected a single character, got %d in %q", utf8.RuneCountInString(v), v)
+	}
+	return r, nil
+}
+
+// parseChar handles the `rune:"true"` hint for rune (int32) and byte (uint8) fields, which otherwise parse as numbers
+func parseChar(v string, toType reflect.Type) (any, error) {
+	r, err := ParseRune(v)
+	if err != nil {
+		return reflect.Zero(toType).Interface(), err
+	}
+	if toType.Kind() == reflect.Uint8 && r > utf8.RuneSelf-1 {
+		return reflect.Zero(toType).Interface(), fmt.Errorf("%q is not a single byte character", v)
+	}
+	return reflect.ValueOf(r).Convert(toType).Interface(), nil
+}
+
+// Template is the common behavior of text/template and html/template templates, for fields whose engine is
+// chosen by the `templateEngine` hint
+type Template interface {
+	Name() string
+	Execute(w io.Writer, data any) error
+}
+
+// templateName names templates parsed from configuration values
+const templateName = "config"
+
+func parseTextTemplate(v string, parserHints Hints) (any, error) {
+	return texttemplate.New(templateName).Parse(v)
+}
+
+func parseHTMLTemplate(v string, parserHints Hints) (any, error) {
+	return htmltemplate.New(templateName).Parse(v)
+}
+
+// parseTemplate handles the Template interface, using text/template unless the `templateEngine:"html"` hint
+// selects html/template's contextual auto-escaping.  Templates are parsed when the value is, so syntax errors are
+// reported at load time.
+func parseTemplate(v string, parserHints Hints) (any, error) {
+	engine, err := parserHints.GetString("templateEngine")
+	if err != nil {
+		return Template(nil), err
+	}
+	switch engine {
+	case "", "text":
+		return texttemplate.New(templateName).Parse(v)
+	case "html":
+		return htmltemplate.New(templateName).Parse(v)
+	}
+	return Template(nil), fmt.Errorf("unknown templateEngine %q, expected text or html", engine)
+}
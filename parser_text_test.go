@@ -0,0 +1,254 @@
+package patchpanel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	htmltemplate "html/template"
+	"regexp"
+	"testing"
+	texttemplate "text/template"
+)
+
+type TextStruct struct {
+	Matcher     *regexp.Regexp `default:"^[a-z]+-[0-9]+$"`
+	Longest     *regexp.Regexp `default:"a+|a+b" regexpMode:"posix"`
+	Unbalanced  *regexp.Regexp `default:"([a-z]"`
+	PerlInPOSIX *regexp.Regexp `default:"\\d+" regexpMode:"posix"`
+	UnknownMode *regexp.Regexp `default:"a" regexpMode:"pcre"`
+	ExplicitRE2 *regexp.Regexp `default:"\\d+" regexpMode:"re2"`
+}
+
+func Test_regexpParser(t *testing.T) {
+	pp := NewPatchPanel(TokenSeparator, KeyValueSeparator)
+	ts := ToReflectType(TextStruct{})
+	hints := []string{"regexpMode"}
+
+	tests := []struct {
+		name      string
+		fieldName string
+		input     string
+		wantMatch string
+		wantErr   bool
+	}{
+		{name: "re2", fieldName: "Matcher", input: "web-01", wantMatch: "web-01"},
+		{name: "posix leftmost-longest", fieldName: "Longest", input: "aab", wantMatch: "aab"},
+		{name: "invalid expression", fieldName: "Unbalanced", wantErr: true},
+		{name: "perl class rejected in posix mode", fieldName: "PerlInPOSIX", wantErr: true},
+		{name: "unknown mode", fieldName: "UnknownMode", wantErr: true},
+		{name: "explicit re2", fieldName: "ExplicitRE2", input: "x42", wantMatch: "42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ts, hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			re, ok := got.(*regexp.Regexp)
+			if !ok {
+				t.Fatalf("GetDefault() returned %T, want *regexp.Regexp", got)
+			}
+			if match := re.FindString(tt.input); match != tt.wantMatch {
+				t.Errorf("FindString(%q) = %q, want %q", tt.input, match, tt.wantMatch)
+			}
+		})
+	}
+}
+
+type BytesStruct struct {
+	Key       []byte `default:"c2VjcmV0" encoding:"base64"`
+	URLKey    []byte `default:"-_8=" encoding:"base64url"`
+	Digest    []byte `default:"DEADbeef" encoding:"hex"`
+	Plain     []byte `default:"secret"`
+	Unpadded  []byte `default:"c2VjcmV0Cg" encoding:"base64"`
+	OddHex    []byte `default:"abc" encoding:"hex"`
+	Ascii85   []byte `default:"87cURD]i" encoding:"ascii85"`
+	WrongAlph []byte `default:"-_8=" encoding:"base64"`
+}
+
+func Test_bytesParser(t *testing.T) {
+	pp := New()
+	bs := ToReflectType(BytesStruct{})
+	hints := []string{"encoding"}
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      []byte
+		wantErr   bool
+	}{
+		{name: "base64", fieldName: "Key", want: []byte("secret")},
+		{name: "base64url", fieldName: "URLKey", want: []byte{0xfb, 0xff}},
+		{name: "hex mixed case", fieldName: "Digest", want: []byte{0xde, 0xad, 0xbe, 0xef}},
+		{name: "no encoding", fieldName: "Plain", want: []byte("secret")},
+		{name: "missing padding", fieldName: "Unpadded", wantErr: true},
+		{name: "odd length hex", fieldName: "OddHex", wantErr: true},
+		{name: "unknown encoding", fieldName: "Ascii85", wantErr: true},
+		{name: "url alphabet in standard base64", fieldName: "WrongAlph", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, bs, hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !bytes.Equal(got.([]byte), tt.want) {
+				t.Errorf("GetDefault() got = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+type RawJSONStruct struct {
+	Routing json.RawMessage `default:"{\"region\": \"us-east\", \"weights\": [1, 2]}"`
+	List    json.RawMessage `default:" [1,2,3] "`
+	Broken  json.RawMessage `default:"{\"region\": "`
+	Empty   json.RawMessage `default:""`
+}
+
+func Test_rawMessageParser(t *testing.T) {
+	pp := New()
+	rs := ToReflectType(RawJSONStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      string
+		wantErr   bool
+	}{
+		{name: "object", fieldName: "Routing", want: `{"region": "us-east", "weights": [1, 2]}`},
+		{name: "array trimmed", fieldName: "List", want: `[1,2,3]`},
+		{name: "malformed", fieldName: "Broken", wantErr: true},
+		{name: "empty", fieldName: "Empty", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := pp.GetFieldTag(tt.fieldName, "default", rs, []string{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFieldTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && string(got.(json.RawMessage)) != tt.want {
+				t.Errorf("GetFieldTag() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+type RuneStruct struct {
+	Delimiter rune  `default:"," rune:"true"`
+	Tab       rune  `default:"\\t" rune:"true"`
+	Space     rune  `default:" " rune:"true"`
+	Unicode   rune  `default:"·" rune:"true"`
+	Escaped   rune  `default:"\\u00a0" rune:"true"`
+	Quote     byte  `default:"'" rune:"true"`
+	Number    int32 `default:"44"`
+	TooMany   rune  `default:"ab" rune:"true"`
+	Empty     rune  `default:"" rune:"true"`
+	BadEscape rune  `default:"\\q" rune:"true"`
+	WideByte  byte  `default:"é" rune:"true"`
+}
+
+func Test_runeParser(t *testing.T) {
+	pp := New()
+	rs := ToReflectType(RuneStruct{})
+	hints := []string{"rune"}
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantErr   bool
+	}{
+		{name: "comma", fieldName: "Delimiter", want: ','},
+		{name: "tab escape", fieldName: "Tab", want: '\t'},
+		{name: "space is significant", fieldName: "Space", want: ' '},
+		{name: "multibyte character", fieldName: "Unicode", want: '·'},
+		{name: "unicode escape", fieldName: "Escaped", want: '\u00a0'},
+		{name: "byte", fieldName: "Quote", want: byte('\'')},
+		{name: "int32 without hint is a number", fieldName: "Number", want: int32(44)},
+		{name: "more than one character", fieldName: "TooMany", wantErr: true},
+		{name: "empty", fieldName: "Empty", wantErr: true},
+		{name: "unknown escape", fieldName: "BadEscape", wantErr: true},
+		{name: "non-ascii byte", fieldName: "WideByte", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := pp.GetFieldTag(tt.fieldName, "default", rs, hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFieldTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetFieldTag() got = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+type TemplateStruct struct {
+	Greeting *texttemplate.Template `default:"Hello, {{.}}!"`
+	Page     *htmltemplate.Template `default:"<p>{{.}}</p>"`
+	Broken   *texttemplate.Template `default:"Hello, {{.Name"`
+	Text     Template               `default:"<b>{{.}}</b>"`
+	HTML     Template               `default:"<b>{{.}}</b>" templateEngine:"html"`
+	Unknown  Template               `default:"{{.}}" templateEngine:"jinja"`
+}
+
+func Test_templateParsers(t *testing.T) {
+	pp := New()
+	ts := ToReflectType(TemplateStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		data      string
+		want      string
+		wantErr   bool
+	}{
+		{name: "text", fieldName: "Greeting", data: "ops", want: "Hello, ops!"},
+		{name: "html escapes", fieldName: "Page", data: "<script>", want: "<p>&lt;script&gt;</p>"},
+		{name: "syntax error", fieldName: "Broken", wantErr: true},
+		{name: "interface defaults to text", fieldName: "Text", data: "<i>", want: "<b><i></b>"},
+		{name: "interface html engine", fieldName: "HTML", data: "<i>", want: "<b>&lt;i&gt;</b>"},
+		{name: "unknown engine", fieldName: "Unknown", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ts, []string{"templateEngine"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var out bytes.Buffer
+			if err := got.(Template).Execute(&out, tt.data); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Execute() got = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+
+	// the interface is populated from either engine
+	var dst TemplateStruct
+	err := pp.Populate(&dst)
+	if dst.HTML == nil || dst.Text == nil {
+		t.Errorf("Populate() left Template fields nil")
+	}
+	if _, ok := dst.HTML.(*htmltemplate.Template); !ok {
+		t.Errorf("Populate() HTML = %T, want *html/template.Template", dst.HTML)
+	}
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Errorf("Populate() error = %v, want FieldErrors for the broken templates", err)
+	}
+}
@@ -0,0 +1,347 @@
+package patchpanel
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseLocation handles timezone names understood by time.LoadLocation, e.g. "America/Chicago" or "UTC".
+// "Local" resolves to the host's configured zone.
+func parseLocation(v string, parserHints Hints) (any, error) {
+	loc, err := time.LoadLocation(strings.TrimSpace(v))
+	if err != nil {
+		return (*time.Location)(nil), err
+	}
+	return loc, nil
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"sun":       time.Sunday,
+	"monday":    time.Monday,
+	"mon":       time.Monday,
+	"tuesday":   time.Tuesday,
+	"tue":       time.Tuesday,
+	"tues":      time.Tuesday,
+	"wednesday": time.Wednesday,
+	"wed":       time.Wednesday,
+	"thursday":  time.Thursday,
+	"thu":       time.Thursday,
+	"thurs":     time.Thursday,
+	"friday":    time.Friday,
+	"fri":       time.Friday,
+	"saturday":  time.Saturday,
+	"sat":       time.Saturday,
+}
+
+// timeLayout resolves a timeFormat hint: a name from timeFormatMap (e.g. "RFC3339" or "Kitchen"), matched exactly,
+// or otherwise a Go reference layout used verbatim, e.g. "2006-01-02 15:04".  A hint that has no layout elements,
+// or that is within a couple of edits of a name (e.g. "RFC339"), is rejected rather than being used as a layout
+// that can only match itself, or almost nothing.
+func timeLayout(format string) (string, error) {
+	if layout, ok := timeFormatMap[format]; ok {
+		return layout, nil
+	}
+	// a layout without any elements formats to itself
+	if time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(format) == format {
+		return "", fmt.Errorf("unknown timeFormat provided: %q", format)
+	}
+	for name := range timeFormatMap {
+		if editDistance(strings.ToLower(format), strings.ToLower(name)) <= 2 {
+			return "", fmt.Errorf("unknown timeFormat provided: %q, did you mean %q?", format, name)
+		}
+	}
+	return format, nil
+}
+
+// editDistance is the Levenshtein distance between a and b, in runes
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
+
+// epochFormats are the timeFormat names that read the value as an integer offset from the Unix epoch
+var epochFormats = map[string]func(int64) time.Time{
+	"unix":      func(n int64) time.Time { return time.Unix(n, 0) },
+	"unixmilli": time.UnixMilli,
+	"unixmicro": time.UnixMicro,
+	"unixnano":  func(n int64) time.Time { return time.Unix(0, n) },
+}
+
+// parseTime handles time.Time, in RFC 3339 unless the `timeFormat` hint names other layouts.  Several layouts
+// may be listed, split on the token separator, e.g. `timeFormat:"RFC3339·DateOnly·Kitchen"`; each is tried in
+// order and the first that matches is used.  The formats "unix", "unixmilli", "unixmicro" and "unixnano" read
+// epoch integers.
+//
+// Values without zone information are read as UTC, or in the zone named by the `timeLocation` hint, e.g.
+// `timeFormat:"Kitchen" timeLocation:"America/New_York"`.  Epoch times are reported in that zone.
+func (pc *PatchPanel) parseTime(v string, parserHints Hints) (any, error) {
+	loc := time.UTC
+	if parserHints.Has("timeLocation") {
+		name, err := parserHints.GetString("timeLocation")
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc, err = time.LoadLocation(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	// if we haven't been told how to parse this time, try RFC 3339
+	formats := []string{"RFC3339"}
+	// did the user request a time format?
+	if parserHints.Has("timeFormat") {
+		var err error
+		formats, err = parserHints.GetList("timeFormat", pc.tokenSeparator)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	// resolve every layout first so that a mistyped format is reported even when an earlier one matches
+	layouts := make([]string, len(formats))
+	for i, format := range formats {
+		if _, ok := epochFormats[format]; ok {
+			layouts[i] = format
+			continue
+		}
+		layout, err := timeLayout(format)
+		if err != nil {
+			return time.Time{}, err
+		}
+		layouts[i] = layout
+	}
+
+	var parseErr error
+	for _, layout := range layouts {
+		if fromEpoch, ok := epochFormats[layout]; ok {
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err == nil {
+				return fromEpoch(n).In(loc), nil
+			}
+			parseErr = fmt.Errorf("invalid %s timestamp %q: %w", layout, v, err)
+			continue
+		}
+		val, err := time.ParseInLocation(layout, v, loc)
+		if err == nil {
+			return val, nil
+		}
+		parseErr = err
+	}
+	if len(formats) == 1 {
+		return time.Time{}, parseErr
+	}
+	return time.Time{}, fmt.Errorf("%q matches none of the time formats %s: %w",
+		v, strings.Join(formats, pc.tokenSeparator), parseErr)
+}
+
+// extendedDurationUnits are the units ParseExtendedDuration adds to those of time.ParseDuration
+var extendedDurationUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// ParseExtendedDuration parses durations as time.ParseDuration does, additionally accepting "d" (24h) and
+// "w" (7d) units, e.g. "2d", "1w" or "1d12h".  Days are always 24 hours long.
+func ParseExtendedDuration(v string) (time.Duration, error) {
+	orig := v
+	neg := false
+	if v != "" && (v[0] == '-' || v[0] == '+') {
+		neg = v[0] == '-'
+		v = v[1:]
+	}
+	if v == "0" {
+		return 0, nil
+	}
+	if v == "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	var total time.Duration
+	for v != "" {
+		// each component is a number followed by a unit
+		i := 0
+		for i < len(v) && (v[i] >= '0' && v[i] <= '9' || v[i] == '.') {
+			i++
+		}
+		j := i
+		for j < len(v) && !(v[j] >= '0' && v[j] <= '9' || v[j] == '.') {
+			j++
+		}
+		number, unit := v[:i], v[i:j]
+		v = v[j:]
+
+		var d time.Duration
+		if size, ok := extendedDurationUnits[unit]; ok {
+			f, err := strconv.ParseFloat(number, 64)
+			if err != nil || number == "" {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			if f*float64(size) > math.MaxInt64 {
+				return 0, fmt.Errorf("invalid duration %q: out of range", orig)
+			}
+			d = time.Duration(f * float64(size))
+		} else {
+			var err error
+			d, err = time.ParseDuration(number + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+		}
+		if total > math.MaxInt64-d {
+			return 0, fmt.Errorf("invalid duration %q: out of range", orig)
+		}
+		total += d
+	}
+
+	if neg {
+		return -total, nil
+	}
+	return total, nil
+}
+
+// parseDuration handles time.Duration.  The `durationUnits:"extended"` hint accepts day and week units;
+// see ParseExtendedDuration.
+func parseDuration(v string, parserHints Hints) (any, error) {
+	units, err := parserHints.GetString("durationUnits")
+	if err != nil {
+		return time.Duration(0), err
+	}
+	switch units {
+	case "":
+		return time.ParseDuration(v)
+	case "extended":
+		return ParseExtendedDuration(v)
+	}
+	return time.Duration(0), fmt.Errorf("unknown durationUnits %q, expected extended", units)
+}
+
+// ParseWeekday accepts English weekday names and their common abbreviations, case-insensitively,
+// e.g. "Tuesday", "tue" or "TUES", as well as the numbers 0 (Sunday) through 6 (Saturday) used by
+// time.Weekday and cron.
+func ParseWeekday(v string) (time.Weekday, error) {
+	v = strings.TrimSpace(v)
+	if n, err := strconv.Atoi(v); err == nil {
+		if n < 0 || n > 6 {
+			return time.Sunday, fmt.Errorf("weekday number %d out of range 0-6", n)
+		}
+		return time.Weekday(n), nil
+	}
+	day, ok := weekdayNames[strings.ToLower(v)]
+	if !ok {
+		return time.Sunday, fmt.Errorf("unknown weekday %q", v)
+	}
+	return day, nil
+}
+
+func parseWeekday(v string, parserHints Hints) (any, error) {
+	return ParseWeekday(v)
+}
+
+// WeekdaySet is a set of weekdays stored as a bitmask, with bit n set for time.Weekday(n).
+// It parses from a list such as "mon·wed·fri", and ranges such as "mon-fri" may be listed too.
+type WeekdaySet uint8
+
+// Weekdays are Monday through Friday
+const Weekdays WeekdaySet = 1<<time.Monday | 1<<time.Tuesday | 1<<time.Wednesday | 1<<time.Thursday | 1<<time.Friday
+
+// Weekend is Saturday and Sunday
+const Weekend WeekdaySet = 1<<time.Saturday | 1<<time.Sunday
+
+// Contains reports whether day is in the set
+func (ws WeekdaySet) Contains(day time.Weekday) bool {
+	return ws&(1<<day) != 0
+}
+
+// Days lists the days in the set, starting with Sunday
+func (ws WeekdaySet) Days() []time.Weekday {
+	var days []time.Weekday
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if ws.Contains(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// String renders the set as abbreviated day names separated by TokenSeparator, e.g. "Mon·Wed·Fri"
+func (ws WeekdaySet) String() string {
+	var names []string
+	for _, day := range ws.Days() {
+		names = append(names, day.String()[:3])
+	}
+	return strings.Join(names, TokenSeparator)
+}
+
+// parseWeekdayList reads weekdays and weekday ranges split on sep.  Ranges wrap past Saturday,
+// so "fri-mon" is Friday through Monday.
+func parseWeekdayList(v string, sep string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, token := range splitTokens(v, sep) {
+		from, to, isRange := strings.Cut(token, "-")
+		if !isRange {
+			day, err := ParseWeekday(token)
+			if err != nil {
+				return nil, err
+			}
+			days = append(days, day)
+			continue
+		}
+
+		start, err := ParseWeekday(from)
+		if err != nil {
+			return nil, err
+		}
+		end, err := ParseWeekday(to)
+		if err != nil {
+			return nil, err
+		}
+		for day := start; ; day = (day + 1) % 7 {
+			days = append(days, day)
+			if day == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func (pc *PatchPanel) parseWeekdaySet(v string, parserHints Hints) (any, error) {
+	days, err := parseWeekdayList(v, pc.separator(parserHints))
+	if err != nil {
+		return WeekdaySet(0), err
+	}
+	var ws WeekdaySet
+	for _, day := range days {
+		ws |= 1 << day
+	}
+	return ws, nil
+}
+
+func (pc *PatchPanel) parseWeekdays(v string, parserHints Hints) (any, error) {
+	days, err := parseWeekdayList(v, pc.separator(parserHints))
+	if err != nil {
+		return []time.Weekday(nil), err
+	}
+	return days, nil
+}
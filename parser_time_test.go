@@ -0,0 +1,276 @@
+package patchpanel
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type TimeStruct struct {
+	Reporting *time.Location `default:"America/Chicago"`
+	Universal *time.Location `default:"UTC"`
+	Nowhere   *time.Location `default:"Mars/Olympus_Mons"`
+}
+
+func Test_locationParser(t *testing.T) {
+	pp := NewPatchPanel(TokenSeparator, KeyValueSeparator)
+	ts := ToReflectType(TimeStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      string
+		wantErr   bool
+	}{
+		{name: "named zone", fieldName: "Reporting", want: "America/Chicago"},
+		{name: "utc", fieldName: "Universal", want: "UTC"},
+		{name: "unknown zone", fieldName: "Nowhere", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ts, []string{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			loc, ok := got.(*time.Location)
+			if !ok {
+				t.Fatalf("GetDefault() returned %T, want *time.Location", got)
+			}
+			if loc.String() != tt.want {
+				t.Errorf("GetDefault() got = %v, want %v", loc, tt.want)
+			}
+		})
+	}
+}
+
+type TimeFormatStruct struct {
+	Default   time.Time `default:"2024-03-01T10:30:00Z"`
+	Named     time.Time `default:"2024-03-01" timeFormat:"DateOnly"`
+	Layout    time.Time `default:"2024-03-01 10:30" timeFormat:"2006-01-02 15:04"`
+	Mismatch  time.Time `default:"03/01/2024" timeFormat:"2006-01-02 15:04"`
+	NoLayout  time.Time `default:"anything" timeFormat:"IsoFormat"`
+	SlashDate time.Time `default:"01/03/2024" timeFormat:"02/01/2006"`
+	Fallback  time.Time `default:"2024-03-01" timeFormat:"RFC3339·DateOnly·Kitchen"`
+	FirstWins time.Time `default:"2024-03-01T10:30:00Z" timeFormat:"RFC3339·DateOnly"`
+	NoneMatch time.Time `default:"tomorrow" timeFormat:"RFC3339·DateOnly"`
+	BadInList time.Time `default:"2024-03-01" timeFormat:"DateOnly·IsoFormat"`
+	Unix      time.Time `default:"1709289000" timeFormat:"unix"`
+	UnixMilli time.Time `default:"1709289000250" timeFormat:"unixmilli"`
+	UnixMicro time.Time `default:"1709289000000250" timeFormat:"unixmicro"`
+	UnixNano  time.Time `default:"1709289000000000250" timeFormat:"unixnano"`
+	NotEpoch  time.Time `default:"2024-03-01" timeFormat:"unix"`
+	EpochOr   time.Time `default:"2024-03-01" timeFormat:"unix·DateOnly"`
+	InZone    time.Time `default:"2024-03-01 10:30" timeFormat:"2006-01-02 15:04" timeLocation:"America/New_York"`
+	ZoneWins  time.Time `default:"2024-03-01T10:30:00Z" timeLocation:"America/New_York"`
+	EpochZone time.Time `default:"1709289000" timeFormat:"unix" timeLocation:"Asia/Tokyo"`
+	BadZone   time.Time `default:"2024-03-01" timeFormat:"DateOnly" timeLocation:"Mars/Olympus_Mons"`
+}
+
+func Test_timeParser(t *testing.T) {
+	pp := New()
+	ts := ToReflectType(TimeFormatStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      time.Time
+		zone      string
+		wantErr   bool
+	}{
+		{name: "rfc3339 by default", fieldName: "Default", want: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "named format", fieldName: "Named", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "raw layout", fieldName: "Layout", want: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "raw layout mismatch", fieldName: "Mismatch", wantErr: true},
+		{name: "not a layout", fieldName: "NoLayout", wantErr: true},
+		{name: "day first layout", fieldName: "SlashDate", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "fallback format", fieldName: "Fallback", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "first match wins", fieldName: "FirstWins", want: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "no format matches", fieldName: "NoneMatch", wantErr: true},
+		{name: "unknown format in list", fieldName: "BadInList", wantErr: true},
+		{name: "unix seconds", fieldName: "Unix", want: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "unix milliseconds", fieldName: "UnixMilli", want: time.Date(2024, 3, 1, 10, 30, 0, 250e6, time.UTC)},
+		{name: "unix microseconds", fieldName: "UnixMicro", want: time.Date(2024, 3, 1, 10, 30, 0, 250e3, time.UTC)},
+		{name: "unix nanoseconds", fieldName: "UnixNano", want: time.Date(2024, 3, 1, 10, 30, 0, 250, time.UTC)},
+		{name: "not an epoch", fieldName: "NotEpoch", wantErr: true},
+		{name: "epoch or date", fieldName: "EpochOr", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "time location", fieldName: "InZone", want: time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC), zone: "America/New_York"},
+		{name: "explicit offset wins", fieldName: "ZoneWins", want: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "epoch in location", fieldName: "EpochZone", want: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), zone: "Asia/Tokyo"},
+		{name: "unknown location", fieldName: "BadZone", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ts, []string{"timeFormat", "timeLocation"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.(time.Time).Equal(tt.want) {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+			if tt.zone != "" && got.(time.Time).Location().String() != tt.zone {
+				t.Errorf("GetDefault() location = %v, want %v", got.(time.Time).Location(), tt.zone)
+			}
+		})
+	}
+}
+
+type DurationStruct struct {
+	Standard    time.Duration `default:"90m"`
+	NoDays      time.Duration `default:"2d"`
+	Days        time.Duration `default:"2d" durationUnits:"extended"`
+	Weeks       time.Duration `default:"1w" durationUnits:"extended"`
+	Mixed       time.Duration `default:"1d12h30m" durationUnits:"extended"`
+	Fractional  time.Duration `default:"1.5d" durationUnits:"extended"`
+	Negative    time.Duration `default:"-1w2d" durationUnits:"extended"`
+	StdUnits    time.Duration `default:"250ms" durationUnits:"extended"`
+	Overflow    time.Duration `default:"1000000w" durationUnits:"extended"`
+	Garbage     time.Duration `default:"d" durationUnits:"extended"`
+	UnknownUnit time.Duration `default:"2d" durationUnits:"lunar"`
+}
+
+func Test_durationParser(t *testing.T) {
+	pp := New()
+	ds := ToReflectType(DurationStruct{})
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      time.Duration
+		wantErr   bool
+	}{
+		{name: "standard", fieldName: "Standard", want: 90 * time.Minute},
+		{name: "days need the hint", fieldName: "NoDays", wantErr: true},
+		{name: "days", fieldName: "Days", want: 2 * day},
+		{name: "weeks", fieldName: "Weeks", want: 7 * day},
+		{name: "mixed", fieldName: "Mixed", want: day + 12*time.Hour + 30*time.Minute},
+		{name: "fractional", fieldName: "Fractional", want: 36 * time.Hour},
+		{name: "negative", fieldName: "Negative", want: -9 * day},
+		{name: "standard units", fieldName: "StdUnits", want: 250 * time.Millisecond},
+		{name: "overflow", fieldName: "Overflow", wantErr: true},
+		{name: "missing number", fieldName: "Garbage", wantErr: true},
+		{name: "unknown units hint", fieldName: "UnknownUnit", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ds, []string{"durationUnits"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type WeekdayStruct struct {
+	Reboot      time.Weekday   `default:"Tuesday"`
+	Abbrev      time.Weekday   `default:"sat"`
+	Unknown     time.Weekday   `default:"someday"`
+	Number      time.Weekday   `default:"2"`
+	SundayZero  time.Weekday   `default:"0"`
+	OutOfRange  time.Weekday   `default:"7"`
+	NumberRange WeekdaySet     `default:"1-5"`
+	Maintenance WeekdaySet     `default:"mon·wed·fri"`
+	Workweek    WeekdaySet     `default:"mon-fri"`
+	WrapAround  WeekdaySet     `default:"fri-mon"`
+	Ordered     []time.Weekday `default:"fri·MON"`
+	BadList     WeekdaySet     `default:"mon·funday"`
+}
+
+func Test_weekdayParsers(t *testing.T) {
+	pp := New()
+	ws := ToReflectType(WeekdayStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantErr   bool
+	}{
+		{name: "full name", fieldName: "Reboot", want: time.Tuesday},
+		{name: "abbreviation", fieldName: "Abbrev", want: time.Saturday},
+		{name: "unknown", fieldName: "Unknown", wantErr: true},
+		{name: "number", fieldName: "Number", want: time.Tuesday},
+		{name: "sunday is zero", fieldName: "SundayZero", want: time.Sunday},
+		{name: "number out of range", fieldName: "OutOfRange", wantErr: true},
+		{name: "numeric range", fieldName: "NumberRange", want: Weekdays},
+		{name: "set", fieldName: "Maintenance", want: WeekdaySet(1<<time.Monday | 1<<time.Wednesday | 1<<time.Friday)},
+		{name: "range", fieldName: "Workweek", want: Weekdays},
+		{name: "wrapping range", fieldName: "WrapAround", want: Weekend | 1<<time.Friday | 1<<time.Monday},
+		{name: "slice keeps order", fieldName: "Ordered", want: []time.Weekday{time.Friday, time.Monday}},
+		{name: "bad list entry", fieldName: "BadList", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ws, []string{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekdaySet(t *testing.T) {
+	if !Weekdays.Contains(time.Monday) || Weekdays.Contains(time.Sunday) {
+		t.Errorf("WeekdaySet.Contains() incorrect for Weekdays")
+	}
+	if got := Weekend.String(); got != "Sun·Sat" {
+		t.Errorf("WeekdaySet.String() = %v", got)
+	}
+	if got := len(Weekdays.Days()); got != 5 {
+		t.Errorf("WeekdaySet.Days() returned %d days", got)
+	}
+}
+
+func Test_timeLayout(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{format: "RFC3339", want: time.RFC3339},
+		{format: "Kitchen", want: time.Kitchen},
+		{format: "2006-01-02T15:04:05Z07:00", want: "2006-01-02T15:04:05Z07:00"},
+		{format: "Jan _2 15:04:05.000 MST", want: "Jan _2 15:04:05.000 MST"},
+		{format: "Monday, 02-Jan-06 3:04PM", want: "Monday, 02-Jan-06 3:04PM"},
+		{format: "01/06", want: "01/06"},
+		{format: "Mon", want: "Mon"},
+		{format: "Mon 15:04", want: "Mon 15:04"},
+		{format: "Mon Jan _2 15:04", want: "Mon Jan _2 15:04"},
+		{format: http.TimeFormat, want: http.TimeFormat},
+		{format: "2006-01-02 15:04:05 UTC", want: "2006-01-02 15:04:05 UTC"},
+		{format: "2006-01-02 at 15:04", want: "2006-01-02 at 15:04"},
+		{format: "RFC339", wantErr: true},
+		{format: "RFC1132Z", wantErr: true},
+		{format: "rfc3339", wantErr: true},
+		{format: "Kitchn", wantErr: true},
+		{format: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := timeLayout(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("timeLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("timeLayout() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
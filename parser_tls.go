@@ -0,0 +1,106 @@
+package patchpanel
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// TLSVersion is a crypto/tls protocol version, e.g. tls.VersionTLS12, parsed from "1.2", "TLS1.2" or "TLS 1.2".
+// Convert it to uint16 for tls.Config's MinVersion and MaxVersion.
+type TLSVersion uint16
+
+var tlsVersions = map[string]TLSVersion{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func (v TLSVersion) String() string {
+	return tls.VersionName(uint16(v))
+}
+
+// ParseTLSVersion accepts TLS versions with or without a "TLS" prefix, case-insensitively
+func ParseTLSVersion(v string) (TLSVersion, error) {
+	version := strings.TrimSpace(v)
+	if len(version) >= 3 && strings.EqualFold(version[:3], "tls") {
+		version = strings.TrimSpace(version[3:])
+	}
+	if tv, ok := tlsVersions[version]; ok {
+		return tv, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version %q, expected 1.0, 1.1, 1.2 or 1.3", v)
+}
+
+func parseTLSVersion(v string, parserHints Hints) (any, error) {
+	return ParseTLSVersion(v)
+}
+
+// CipherSuite is a crypto/tls cipher suite ID, parsed from its standard name such as
+// "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256".  Lists of suites ([]CipherSuite) are split on the token separator.
+type CipherSuite uint16
+
+func (cs CipherSuite) String() string {
+	return tls.CipherSuiteName(uint16(cs))
+}
+
+// ParseCipherSuite looks up a cipher suite by name, case-insensitively.  Suites crypto/tls considers insecure
+// are rejected unless allowInsecure is set.
+func ParseCipherSuite(v string, allowInsecure bool) (CipherSuite, error) {
+	name := strings.TrimSpace(v)
+	for _, suite := range tls.CipherSuites() {
+		if strings.EqualFold(suite.Name, name) {
+			return CipherSuite(suite.ID), nil
+		}
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if strings.EqualFold(suite.Name, name) {
+			if !allowInsecure {
+				return 0, fmt.Errorf("cipher suite %s is insecure; set allowInsecure:\"true\" to permit it", suite.Name)
+			}
+			return CipherSuite(suite.ID), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown cipher suite %q", v)
+}
+
+// parseCipherSuite handles CipherSuite, with the `allowInsecure:"true"` hint permitting insecure suites
+func parseCipherSuite(v string, parserHints Hints) (any, error) {
+	allowInsecure, err := parserHints.GetBool("allowInsecure")
+	if err != nil {
+		return CipherSuite(0), err
+	}
+	return ParseCipherSuite(v, allowInsecure)
+}
+
+// CipherSuiteIDs converts suites to the IDs used by tls.Config's CipherSuites
+func CipherSuiteIDs(suites []CipherSuite) []uint16 {
+	ids := make([]uint16, len(suites))
+	for i, suite := range suites {
+		ids[i] = uint16(suite)
+	}
+	return ids
+}
+
+// parseCertificate handles tls.Certificate from a "cert.pem:key.pem" pair of PEM files split on the key/value
+// separator, loaded when the value is parsed so that unreadable or mismatched files are reported at startup
+// rather than at the first handshake
+func (pc *PatchPanel) parseCertificate(v string, parserHints Hints) (any, error) {
+	certFile, keyFile, ok := strings.Cut(v, pc.keyValueSeparator)
+	certFile, keyFile = strings.TrimSpace(certFile), strings.TrimSpace(keyFile)
+	if !ok || certFile == "" || keyFile == "" {
+		return tls.Certificate{}, fmt.Errorf("certificate %q must be a certificate and key file pair, e.g. cert.pem%skey.pem",
+			v, pc.keyValueSeparator)
+	}
+	return tls.LoadX509KeyPair(certFile, keyFile)
+}
+
+func (pc *PatchPanel) parseCertificatePtr(v string, parserHints Hints) (any, error) {
+	cert, err := pc.parseCertificate(v, parserHints)
+	if err != nil {
+		return (*tls.Certificate)(nil), err
+	}
+	c := cert.(tls.Certificate)
+	return &c, nil
+}
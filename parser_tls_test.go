@@ -0,0 +1,151 @@
+package patchpanel
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type TLSStruct struct {
+	MinVersion  TLSVersion    `default:"1.2"`
+	Prefixed    TLSVersion    `default:"TLS1.3"`
+	Spaced      TLSVersion    `default:"tls 1.2"`
+	SSL         TLSVersion    `default:"SSLv3"`
+	Suite       CipherSuite   `default:"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"`
+	LowerSuite  CipherSuite   `default:"tls_aes_128_gcm_sha256"`
+	Insecure    CipherSuite   `default:"TLS_RSA_WITH_RC4_128_SHA"`
+	AllowRC4    CipherSuite   `default:"TLS_RSA_WITH_RC4_128_SHA" allowInsecure:"true"`
+	Unknown     CipherSuite   `default:"TLS_MADE_UP"`
+	Suites      []CipherSuite `default:"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256·TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"`
+	SuitesComma []CipherSuite `default:"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_MADE_UP" sep:","`
+}
+
+func Test_tlsParsers(t *testing.T) {
+	pp := New()
+	ts := ToReflectType(TLSStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantErr   bool
+	}{
+		{name: "version", fieldName: "MinVersion", want: TLSVersion(tls.VersionTLS12)},
+		{name: "prefixed version", fieldName: "Prefixed", want: TLSVersion(tls.VersionTLS13)},
+		{name: "spaced version", fieldName: "Spaced", want: TLSVersion(tls.VersionTLS12)},
+		{name: "unsupported version", fieldName: "SSL", wantErr: true},
+		{name: "suite", fieldName: "Suite", want: CipherSuite(tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256)},
+		{name: "lower case suite", fieldName: "LowerSuite", want: CipherSuite(tls.TLS_AES_128_GCM_SHA256)},
+		{name: "insecure suite", fieldName: "Insecure", wantErr: true},
+		{name: "allowed insecure suite", fieldName: "AllowRC4", want: CipherSuite(tls.TLS_RSA_WITH_RC4_128_SHA)},
+		{name: "unknown suite", fieldName: "Unknown", wantErr: true},
+		{name: "suite list", fieldName: "Suites", want: []CipherSuite{
+			CipherSuite(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256), CipherSuite(tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384),
+		}},
+		{name: "bad suite in list", fieldName: "SuitesComma", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ts, []string{"allowInsecure", "sep"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	ids := CipherSuiteIDs([]CipherSuite{CipherSuite(tls.TLS_AES_128_GCM_SHA256)})
+	if !reflect.DeepEqual(ids, []uint16{tls.TLS_AES_128_GCM_SHA256}) {
+		t.Errorf("CipherSuiteIDs() = %v", ids)
+	}
+	if TLSVersion(tls.VersionTLS13).String() != "TLS 1.3" {
+		t.Errorf("TLSVersion.String() = %q", TLSVersion(tls.VersionTLS13).String())
+	}
+}
+
+// writeKeyPair writes a self-signed certificate and its key as PEM files in dir
+func writeKeyPair(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "patchpanel.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func Test_certificateParser(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir)
+	otherCert, _ := writeKeyPair(t, t.TempDir())
+	pp := New()
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "pair", value: certFile + ":" + keyFile},
+		{name: "spaces", value: certFile + " : " + keyFile},
+		{name: "missing key", value: certFile, wantErr: true},
+		{name: "missing file", value: certFile + ":" + filepath.Join(dir, "nope.pem"), wantErr: true},
+		{name: "mismatched pair", value: otherCert + ":" + keyFile, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.coerce(tt.value, reflect.TypeOf(tls.Certificate{}), Hints{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("coerce() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && len(got.(tls.Certificate).Certificate) != 1 {
+				t.Errorf("coerce() got = %v, want one certificate", got)
+			}
+		})
+	}
+
+	// the file paths are only known at run time, so the struct is built with them in its tag
+	pp = New(WithValueTag("cert"))
+	field := reflect.StructField{Name: "Cert", Type: reflect.TypeOf(&tls.Certificate{}), Tag: reflect.StructTag(`cert:"` + certFile + ":" + keyFile + `"`)}
+	populated := reflect.New(reflect.StructOf([]reflect.StructField{field}))
+	if err := pp.Populate(populated.Interface()); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	if populated.Elem().Field(0).IsNil() {
+		t.Errorf("Populate() left *tls.Certificate nil")
+	}
+}
@@ -0,0 +1,100 @@
+package patchpanel
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// UUID is a 128 bit universally unique identifier (RFC 9562, formerly RFC 4122).
+type UUID [16]byte
+
+// String renders the canonical form, e.g. "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Version is the version number held in the high nibble of byte 6.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// IsRFCVariant reports whether the variant bits (the high bits of byte 8) are 10, the layout defined by the RFC.
+func (u UUID) IsRFCVariant() bool {
+	return u[8]&0xc0 == 0x80
+}
+
+// ParseUUID accepts the canonical form, the braced form ("{...}"), the URN form ("urn:uuid:..."),
+// and 32 raw hex digits.  Hex digits may be upper or lower case.
+func ParseUUID(v string) (UUID, error) {
+	var u UUID
+	s := strings.TrimSpace(v)
+
+	switch {
+	case strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}"):
+		s = s[1 : len(s)-1]
+	case len(s) > 9 && strings.EqualFold(s[:9], "urn:uuid:"):
+		s = s[9:]
+	}
+
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("invalid UUID %q", v)
+		}
+		s = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return u, fmt.Errorf("invalid UUID %q: unexpected length", v)
+	}
+
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return UUID{}, fmt.Errorf("invalid UUID %q: %w", v, err)
+	}
+	return u, nil
+}
+
+// parseUUIDHinted parses a UUID, and when the `uuidVersion:"4"` hint is present, requires that version
+// along with the RFC variant.
+func parseUUIDHinted(v string, parserHints Hints) (UUID, error) {
+	u, err := ParseUUID(v)
+	if err != nil {
+		return UUID{}, err
+	}
+
+	if parserHints.Has("uuidVersion") {
+		want, err := parserHints.GetInt("uuidVersion")
+		if err != nil {
+			return UUID{}, err
+		}
+		if want < 1 || want > 8 {
+			return UUID{}, fmt.Errorf("uuidVersion parser hint must be between 1 and 8, got %d", want)
+		}
+		if !u.IsRFCVariant() {
+			return UUID{}, fmt.Errorf("UUID %s is not an RFC variant UUID", u)
+		}
+		if u.Version() != want {
+			return UUID{}, fmt.Errorf("UUID %s is version %d, expected version %d", u, u.Version(), want)
+		}
+	}
+	return u, nil
+}
+
+func parseUUID(v string, parserHints Hints) (any, error) {
+	return parseUUIDHinted(v, parserHints)
+}
+
+func parseUUIDBytes(v string, parserHints Hints) (any, error) {
+	u, err := parseUUIDHinted(v, parserHints)
+	return [16]byte(u), err
+}
@@ -0,0 +1,78 @@
+package patchpanel
+
+import (
+	"testing"
+)
+
+func TestParseUUID(t *testing.T) {
+	want := UUID{0xf4, 0x7a, 0xc1, 0x0b, 0x58, 0xcc, 0x43, 0x72, 0xa5, 0x67, 0x0e, 0x02, 0xb2, 0xc3, 0xd4, 0x79}
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{input: "f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		{input: "F47AC10B-58CC-4372-A567-0E02B2C3D479"},
+		{input: "{f47ac10b-58cc-4372-a567-0e02b2c3d479}"},
+		{input: "urn:uuid:f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		{input: "f47ac10b58cc4372a5670e02b2c3d479"},
+		{input: "f47ac10b-58cc-4372-a567-0e02b2c3d47", wantErr: true},
+		{input: "f47ac10b+58cc-4372-a567-0e02b2c3d479", wantErr: true},
+		{input: "z47ac10b-58cc-4372-a567-0e02b2c3d479", wantErr: true},
+		{input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseUUID(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseUUID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != want {
+				t.Errorf("ParseUUID() got = %v, want %v", got, want)
+			}
+		})
+	}
+
+	if want.String() != "f47ac10b-58cc-4372-a567-0e02b2c3d479" {
+		t.Errorf("UUID.String() = %v", want.String())
+	}
+	if want.Version() != 4 || !want.IsRFCVariant() {
+		t.Errorf("UUID version = %d, rfc variant = %v", want.Version(), want.IsRFCVariant())
+	}
+}
+
+type UUIDStruct struct {
+	ID        UUID     `default:"f47ac10b-58cc-4372-a567-0e02b2c3d479" uuidVersion:"4"`
+	Raw       [16]byte `default:"{f47ac10b-58cc-4372-a567-0e02b2c3d479}"`
+	WrongVer  UUID     `default:"6ba7b810-9dad-11d1-80b4-00c04fd430c8" uuidVersion:"4"`
+	NilUUID   UUID     `default:"00000000-0000-0000-0000-000000000000" uuidVersion:"4"`
+	BadHint   UUID     `default:"f47ac10b-58cc-4372-a567-0e02b2c3d479" uuidVersion:"nine"`
+	Unchecked UUID     `default:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+}
+
+func Test_uuidParser(t *testing.T) {
+	pp := New()
+	us := ToReflectType(UUIDStruct{})
+	hints := []string{"uuidVersion"}
+
+	tests := []struct {
+		name      string
+		fieldName string
+		wantErr   bool
+	}{
+		{name: "version 4 requested and present", fieldName: "ID"},
+		{name: "16 byte array", fieldName: "Raw"},
+		{name: "version 1 when 4 requested", fieldName: "WrongVer", wantErr: true},
+		{name: "nil uuid fails variant check", fieldName: "NilUUID", wantErr: true},
+		{name: "invalid hint", fieldName: "BadHint", wantErr: true},
+		{name: "no version requested", fieldName: "Unchecked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pp.GetDefault(tt.fieldName, us, hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package patchpanel
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minutesPerWeek = 7 * 24 * 60
+
+// WindowSlot is a recurring weekly span of wall-clock time, e.g. "sat 02:00-04:00 UTC".
+// A slot whose End is not after its Start runs past midnight into the following day.
+type WindowSlot struct {
+	Day time.Weekday
+	// Start and End are offsets from midnight; End may be 24h
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+// Length is how long the slot lasts
+func (ws WindowSlot) Length() time.Duration {
+	if ws.End > ws.Start {
+		return ws.End - ws.Start
+	}
+	return 24*time.Hour - ws.Start + ws.End
+}
+
+// Contains reports whether t falls inside an occurrence of the slot.  The start is inclusive, the end exclusive.
+func (ws WindowSlot) Contains(t time.Time) bool {
+	local := t.In(ws.Location)
+	// a slot lasts at most a day, so it can only have started today or yesterday
+	for daysBack := 0; daysBack <= 1; daysBack++ {
+		day := local.AddDate(0, 0, -daysBack)
+		if day.Weekday() != ws.Day {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, int(ws.Start/time.Second), 0, ws.Location)
+		end := start.Add(ws.Length())
+		if !t.Before(start) && t.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the slot in the form it is parsed from
+func (ws WindowSlot) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%s %s-%s %s", strings.ToLower(ws.Day.String()[:3]), clock(ws.Start), clock(ws.End), ws.Location)
+}
+
+// weekMinutes places the slot on a week of minutes starting Sunday 00:00 UTC, using the zone's current offset
+func (ws WindowSlot) weekMinutes() (int, int) {
+	_, offset := time.Now().In(ws.Location).Zone()
+	start := int(ws.Day)*24*60 + int(ws.Start/time.Minute) - offset/60
+	start = ((start % minutesPerWeek) + minutesPerWeek) % minutesPerWeek
+	return start, int(ws.Length() / time.Minute)
+}
+
+// overlaps reports whether two slots share any time in the week
+func (ws WindowSlot) overlaps(other WindowSlot) bool {
+	aStart, aLen := ws.weekMinutes()
+	bStart, bLen := other.weekMinutes()
+	within := func(x, start, length int) bool {
+		return ((x-start)%minutesPerWeek+minutesPerWeek)%minutesPerWeek < length
+	}
+	return within(aStart, bStart, bLen) || within(bStart, aStart, aLen)
+}
+
+// MaintenanceWindow is a set of weekly slots during which maintenance is permitted, parsed from a list such as
+// "sat 02:00-04:00 UTC·sun 02:00-04:00 UTC".  Slots may not overlap; zones are compared using their offsets
+// at the time of parsing.
+type MaintenanceWindow struct {
+	Slots []WindowSlot
+}
+
+// Contains reports whether t falls inside any slot of the window
+func (mw MaintenanceWindow) Contains(t time.Time) bool {
+	for _, slot := range mw.Slots {
+		if slot.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the window's slots separated by TokenSeparator
+func (mw MaintenanceWindow) String() string {
+	slots := make([]string, 0, len(mw.Slots))
+	for _, slot := range mw.Slots {
+		slots = append(slots, slot.String())
+	}
+	return strings.Join(slots, TokenSeparator)
+}
+
+// parseClock reads "HH:MM" as an offset from midnight, permitting "24:00" as the end of the day
+func parseClock(v string) (time.Duration, error) {
+	hours, minutes, ok := strings.Cut(v, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", v)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", v)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || len(minutes) != 2 {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", v)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("time of day %q out of range", v)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// ParseWindowSlot reads a single slot of the form "<day> HH:MM-HH:MM [zone]".  The zone defaults to UTC.
+func ParseWindowSlot(v string) (WindowSlot, error) {
+	fields := strings.Fields(v)
+	if len(fields) < 2 || len(fields) > 3 {
+		return WindowSlot{}, fmt.Errorf("invalid window %q, expected \"<day> HH:MM-HH:MM [zone]\"", v)
+	}
+
+	day, err := ParseWeekday(fields[0])
+	if err != nil {
+		return WindowSlot{}, err
+	}
+
+	from, to, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return WindowSlot{}, fmt.Errorf("invalid window span %q, expected HH:MM-HH:MM", fields[1])
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return WindowSlot{}, err
+	}
+	if start == 24*time.Hour {
+		return WindowSlot{}, fmt.Errorf("window %q cannot start at 24:00", v)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return WindowSlot{}, err
+	}
+	if start == end {
+		return WindowSlot{}, fmt.Errorf("window %q has no length", v)
+	}
+
+	loc := time.UTC
+	if len(fields) == 3 {
+		loc, err = time.LoadLocation(fields[2])
+		if err != nil {
+			return WindowSlot{}, err
+		}
+	}
+
+	return WindowSlot{Day: day, Start: start, End: end, Location: loc}, nil
+}
+
+func (pc *PatchPanel) parseMaintenanceWindow(v string, parserHints Hints) (any, error) {
+	var mw MaintenanceWindow
+	for _, token := range splitTokens(v, pc.separator(parserHints)) {
+		slot, err := ParseWindowSlot(token)
+		if err != nil {
+			return MaintenanceWindow{}, err
+		}
+		for _, existing := range mw.Slots {
+			if slot.overlaps(existing) {
+				return MaintenanceWindow{}, fmt.Errorf("window %q overlaps %q", slot, existing)
+			}
+		}
+		mw.Slots = append(mw.Slots, slot)
+	}
+	if len(mw.Slots) == 0 {
+		return MaintenanceWindow{}, errors.New("maintenance window has no slots")
+	}
+	return mw, nil
+}
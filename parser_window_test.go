@@ -0,0 +1,93 @@
+package patchpanel
+
+import (
+	"testing"
+	"time"
+)
+
+type WindowStruct struct {
+	Weekend    MaintenanceWindow `default:"sat 02:00-04:00 UTC·sun 02:00-04:00 UTC"`
+	Overnight  MaintenanceWindow `default:"fri 23:00-01:00"`
+	Chicago    MaintenanceWindow `default:"tue 22:00-24:00 America/Chicago"`
+	Overlap    MaintenanceWindow `default:"sat 02:00-04:00 UTC·sat 03:00-05:00 UTC"`
+	ZoneClash  MaintenanceWindow `default:"sat 02:00-04:00 UTC·sat 03:00-04:00 Etc/GMT-1"`
+	NoLength   MaintenanceWindow `default:"sat 02:00-02:00"`
+	BadClock   MaintenanceWindow `default:"sat 2am-4am"`
+	BadZone    MaintenanceWindow `default:"sat 02:00-04:00 Nowhere/Special"`
+	Empty      MaintenanceWindow `default:"·"`
+	OutOfRange MaintenanceWindow `default:"sat 02:00-25:00"`
+}
+
+func Test_maintenanceWindowParser(t *testing.T) {
+	pp := New()
+	ws := ToReflectType(WindowStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		inside    []time.Time
+		outside   []time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "two slots",
+			fieldName: "Weekend",
+			// 2024-06-01 is a Saturday
+			inside:  []time.Time{time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC), time.Date(2024, 6, 2, 3, 59, 0, 0, time.UTC)},
+			outside: []time.Time{time.Date(2024, 6, 1, 4, 0, 0, 0, time.UTC), time.Date(2024, 6, 3, 3, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:      "slot past midnight",
+			fieldName: "Overnight",
+			inside:    []time.Time{time.Date(2024, 5, 31, 23, 30, 0, 0, time.UTC), time.Date(2024, 6, 1, 0, 30, 0, 0, time.UTC)},
+			outside:   []time.Time{time.Date(2024, 6, 1, 1, 0, 0, 0, time.UTC), time.Date(2024, 5, 31, 22, 59, 0, 0, time.UTC)},
+		},
+		{
+			name:      "slot in a named zone",
+			fieldName: "Chicago",
+			// 2024-06-04 22:30 CDT is 2024-06-05 03:30 UTC
+			inside:  []time.Time{time.Date(2024, 6, 5, 3, 30, 0, 0, time.UTC)},
+			outside: []time.Time{time.Date(2024, 6, 4, 22, 30, 0, 0, time.UTC)},
+		},
+		{name: "overlapping slots", fieldName: "Overlap", wantErr: true},
+		{name: "overlap across zones", fieldName: "ZoneClash", wantErr: true},
+		{name: "zero length", fieldName: "NoLength", wantErr: true},
+		{name: "bad clock", fieldName: "BadClock", wantErr: true},
+		{name: "bad zone", fieldName: "BadZone", wantErr: true},
+		{name: "no slots", fieldName: "Empty", wantErr: true},
+		{name: "hour out of range", fieldName: "OutOfRange", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ws, []string{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			mw := got.(MaintenanceWindow)
+			for _, at := range tt.inside {
+				if !mw.Contains(at) {
+					t.Errorf("Contains(%v) = false, want true", at)
+				}
+			}
+			for _, at := range tt.outside {
+				if mw.Contains(at) {
+					t.Errorf("Contains(%v) = true, want false", at)
+				}
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow_String(t *testing.T) {
+	mw := MaintenanceWindow{Slots: []WindowSlot{
+		{Day: time.Saturday, Start: 2 * time.Hour, End: 4 * time.Hour, Location: time.UTC},
+		{Day: time.Sunday, Start: 23 * time.Hour, End: 30 * time.Minute, Location: time.UTC},
+	}}
+	if got := mw.String(); got != "sat 02:00-04:00 UTC·sun 23:00-00:30 UTC" {
+		t.Errorf("MaintenanceWindow.String() = %v", got)
+	}
+}
@@ -0,0 +1,391 @@
+package patchpanel
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Populate fills the fields of dst, which must be a non-nil pointer to a struct, from each field's value
+// tag (`default` unless changed with WithValueTag).  Every tag on a field is made available to its parser
+// as a hint, so a field such as
+//
+//	Start time.Time `default:"3:00PM" timeFormat:"Kitchen"`
+//
+// needs no further setup.
+//
+// Fields without a value tag are left untouched.  Struct fields that have no registered parser are
+// descended into, as are non-nil pointers to such structs; each struct is visited once, so self-referencing
+// configuration graphs are safe to populate.
+//
+// Each field that fails is reported as a FieldError; all failures are joined into the returned error.
+// Tagged fields of types without a parser fail unless the panel was created WithIgnoreUnknownTypes.
+func (pc *PatchPanel) Populate(dst any) error {
+	rv, err := populateTarget(dst)
+	if err != nil {
+		return pc.misuse(err)
+	}
+
+	st := pc.newPopulateState(pc.tagValue)
+	defer st.release()
+	pc.populateStruct(rv, "", st)
+	return errors.Join(st.errs...)
+}
+
+// FieldInfo identifies a field yielded by PopulateSeq
+type FieldInfo struct {
+	// Path is the dotted path to the field from the populated struct, as in FieldError
+	Path  string
+	Field reflect.StructField
+}
+
+// ResolvedValue is the outcome of populating a single field
+type ResolvedValue struct {
+	// Raw is the value read for the field
+	Raw string
+	// Value is the parsed value assigned to the field, when Err is nil
+	Value any
+	// Err is the field's failure, as reported by Populate
+	Err error
+}
+
+// PopulateSeq is Populate as an iterator: fields are populated as the sequence is consumed, and each field that
+// is assigned or fails is yielded along with its outcome.  Stopping the iteration stops populating, leaving the
+// remaining fields untouched, so huge configurations can report progress or bail out early.
+//
+// An invalid dst or an exceeded limit is yielded as a failure with an empty Field.
+func (pc *PatchPanel) PopulateSeq(dst any) iter.Seq2[FieldInfo, ResolvedValue] {
+	return func(yield func(FieldInfo, ResolvedValue) bool) {
+		rv, err := populateTarget(dst)
+		if err != nil {
+			yield(FieldInfo{}, ResolvedValue{Err: pc.misuse(err)})
+			return
+		}
+
+		st := pc.newPopulateState(pc.tagValue)
+		defer st.release()
+		st.yield = yield
+		pc.populateStruct(rv, "", st)
+	}
+}
+
+// populateTarget returns the struct dst points to
+func populateTarget(dst any) (reflect.Value, error) {
+	rv := reflect.ValueOf(dst)
+	if !rv.IsValid() || rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, InvalidTargetError{
+			Msg: fmt.Sprintf("populate target must be a non-nil pointer to a struct, got %T", dst),
+		}
+	}
+	return rv.Elem(), nil
+}
+
+// fieldValueFunc produces the raw value for the field sF of the struct at prefix, reporting false when the field
+// should be left untouched
+type fieldValueFunc func(sF reflect.StructField, prefix string) (string, bool, error)
+
+// tagValue reads a field's value from the panel's value tag
+func (pc *PatchPanel) tagValue(sF reflect.StructField, prefix string) (string, bool, error) {
+	raw, ok := pc.lookupValueTag(sF)
+	return raw, ok, nil
+}
+
+// lookupValueTag reads the value tag of sF, preferring the tag of the panel's profile, e.g. `default_prod`
+func (pc *PatchPanel) lookupValueTag(sF reflect.StructField) (string, bool) {
+	if pc.profile != "" {
+		if raw, ok := sF.Tag.Lookup(pc.valueTag + "_" + pc.profile); ok {
+			return raw, true
+		}
+	}
+	return sF.Tag.Lookup(pc.valueTag)
+}
+
+// fieldPath is the dotted path of the field name in the struct at prefix.  Paths are only built when needed,
+// as most fields never report one.
+func fieldPath(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// Limits bound a single Populate (or ResolveDeferred).  A zero value for any limit means unlimited.
+type Limits struct {
+	// MaxDepth is the deepest level of struct nesting descended into; the fields of the populated struct are at
+	// depth 0
+	MaxDepth int
+	// MaxFields is the number of fields that may be assigned values
+	MaxFields int
+	// MaxValueBytes is the combined size of all raw values read
+	MaxValueBytes int
+}
+
+// visitKey identifies a struct by address and type; an embedded struct at offset zero shares its parent's address
+type visitKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// populateState is the state of a single walk over a struct
+type populateState struct {
+	// valueFor produces each field's raw value, which comes from source
+	valueFor fieldValueFunc
+	source   string
+	errs     []error
+	// yield, when set, receives each field as it is populated; returning false stops the walk
+	yield func(FieldInfo, ResolvedValue) bool
+	// entryKeys, when set, lists the entries a document provides for the map of structs at path
+	entryKeys func(path string) []string
+	// hasObject, when set, reports whether a document holds an object for the nested struct at path, so that a
+	// nil pointer to that struct is allocated to receive it
+	hasObject func(path string) bool
+	// visited holds the structs already walked, so that pointer cycles terminate
+	visited map[visitKey]bool
+
+	// usage against limits
+	limits Limits
+	depth  int
+	fields int
+	bytes  int
+	// stopped is set once a limit is exceeded or yield returns false, ending the walk
+	stopped bool
+}
+
+// statePool recycles walk state, chiefly the visited map, across Populate calls
+var statePool = sync.Pool{
+	New: func() any { return &populateState{visited: make(map[visitKey]bool)} },
+}
+
+// newPopulateState takes a walk state from statePool; return it with release once the errors have been read
+func (pc *PatchPanel) newPopulateState(valueFor fieldValueFunc) *populateState {
+	st := statePool.Get().(*populateState)
+	st.valueFor = valueFor
+	st.source = SourceTag
+	st.limits = pc.limits
+	return st
+}
+
+func (st *populateState) release() {
+	clear(st.visited)
+	*st = populateState{visited: st.visited}
+	statePool.Put(st)
+}
+
+// exceed records a LimitError and stops the walk
+func (st *populateState) exceed(format string, args ...any) {
+	err := LimitError{Msg: fmt.Sprintf(format, args...)}
+	st.errs = append(st.errs, err)
+	st.stopped = true
+	if st.yield != nil {
+		st.yield(FieldInfo{}, ResolvedValue{Err: err})
+	}
+}
+
+// report records the outcome of the field sF of the struct at prefix, passing it to yield when iterating.  Paths
+// are only built for failures or when yielding.
+func (st *populateState) report(pc *PatchPanel, prefix string, sF reflect.StructField, resolved ResolvedValue) {
+	if resolved.Err != nil {
+		resolved.Err = pc.fieldError(sF, fieldPath(prefix, sF.Name), resolved.Raw, st.source, resolved.Err)
+		st.errs = append(st.errs, resolved.Err)
+	}
+	if st.yield != nil && !st.yield(FieldInfo{Path: fieldPath(prefix, sF.Name), Field: sF}, resolved) {
+		st.stopped = true
+	}
+}
+
+// consume accounts for a raw value about to be assigned to the field name of the struct at prefix, reporting
+// false when doing so exceeds a limit
+func (st *populateState) consume(prefix string, name string, raw string) bool {
+	st.fields++
+	st.bytes += len(raw)
+	if st.limits.MaxFields > 0 && st.fields > st.limits.MaxFields {
+		st.exceed("field %s: more than %d fields populated", fieldPath(prefix, name), st.limits.MaxFields)
+		return false
+	}
+	if st.limits.MaxValueBytes > 0 && st.bytes > st.limits.MaxValueBytes {
+		st.exceed("field %s: values exceed %d bytes", fieldPath(prefix, name), st.limits.MaxValueBytes)
+		return false
+	}
+	return true
+}
+
+// populateStruct walks the fields of the struct value rv, setting each from st.valueFor and recording failures
+// in st.errs.  prefix is the dotted path of rv from the root struct.
+//
+// A struct reachable through several pointers, including a pointer back to one of its parents, is walked only
+// once.
+func (pc *PatchPanel) populateStruct(rv reflect.Value, prefix string, st *populateState) {
+	if st.stopped {
+		return
+	}
+	if st.limits.MaxDepth > 0 && st.depth > st.limits.MaxDepth {
+		st.exceed("field %s: struct nesting exceeds depth %d", prefix, st.limits.MaxDepth)
+		return
+	}
+	st.depth++
+	defer func() { st.depth-- }()
+
+	if rv.CanAddr() {
+		key := visitKey{addr: rv.UnsafeAddr(), typ: rv.Type()}
+		if st.visited[key] {
+			return
+		}
+		st.visited[key] = true
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField() && !st.stopped; i++ {
+		sF := rt.Field(i)
+		if !sF.IsExported() && !sF.Anonymous {
+			pc.checkUnexported(sF, prefix)
+			continue
+		}
+		fieldValue := rv.Field(i)
+
+		// the parser is looked up once and run with parse, rather than through coerce, to keep the
+		// registry lock to one acquisition per field
+		if parserFunc, ok := pc.parser(sF.Type); ok {
+			raw, ok, err := st.valueFor(sF, prefix)
+			if err != nil {
+				st.report(pc, prefix, sF, ResolvedValue{Err: err})
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if !fieldValue.CanSet() {
+				continue
+			}
+			if !st.consume(prefix, sF.Name, raw) {
+				return
+			}
+			hints := pooledFieldHints(sF)
+			val, err := pc.parse(parserFunc, raw, sF.Type, hints)
+			releaseHints(hints)
+			if err == nil {
+				err = assign(fieldValue, val)
+			}
+			if err != nil || st.yield != nil {
+				st.report(pc, prefix, sF, ResolvedValue{Raw: raw, Value: val, Err: err})
+			}
+			continue
+		}
+
+		switch {
+		case sF.Type.Kind() == reflect.Struct:
+			pc.populateStruct(fieldValue, fieldPath(prefix, sF.Name), st)
+		case sF.Type.Kind() == reflect.Pointer && sF.Type.Elem().Kind() == reflect.Struct:
+			path := fieldPath(prefix, sF.Name)
+			if fieldValue.IsNil() && fieldValue.CanSet() && st.hasObject != nil && st.hasObject(path) {
+				// as with encoding/json, a document object for a nil pointer allocates its struct
+				fieldValue.Set(reflect.New(sF.Type.Elem()))
+			}
+			if !fieldValue.IsNil() {
+				pc.populateStruct(fieldValue.Elem(), path, st)
+			}
+		case pc.isStructMap(sF.Type):
+			pc.populateMap(fieldValue, fieldPath(prefix, sF.Name), st)
+		default:
+			// a value was requested for a type we cannot produce
+			if raw, ok, _ := st.valueFor(sF, prefix); ok {
+				err := UnhandledParserTypeError{Msg: fmt.Sprintf("unknown type for parser: %v", sF.Type)}
+				if pc.unknownTypeWarn != nil {
+					pc.unknownTypeWarn(pc.fieldError(sF, fieldPath(prefix, sF.Name), raw, st.source, err))
+					continue
+				}
+				st.report(pc, prefix, sF, ResolvedValue{Raw: raw, Err: err})
+			}
+		}
+	}
+}
+
+// isStructMap reports whether typ is a map from strings to structs (or pointers to structs) without a parser,
+// e.g. map[string]WorkerConfig, whose entries are populated like nested structs
+func (pc *PatchPanel) isStructMap(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Map || typ.Key().Kind() != reflect.String {
+		return false
+	}
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := pc.parser(typ.Elem())
+	return !ok
+}
+
+// populateMap populates each entry of the map of structs m at path as a nested struct at path.key, so every
+// entry gets its own defaults and validation.  Entries are those already in the map plus any a document provides
+// (see st.entryKeys); a nil map is created when there are entries to add.
+func (pc *PatchPanel) populateMap(m reflect.Value, path string, st *populateState) {
+	keys := make(map[string]bool, m.Len())
+	for _, key := range m.MapKeys() {
+		keys[key.String()] = true
+	}
+	if st.entryKeys != nil {
+		for _, key := range st.entryKeys(path) {
+			keys[key] = true
+		}
+	}
+	if len(keys) == 0 || !m.CanSet() {
+		return
+	}
+	if m.IsNil() {
+		m.Set(reflect.MakeMapWithSize(m.Type(), len(keys)))
+	}
+
+	elemType := m.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		if st.stopped {
+			return
+		}
+		mapKey := reflect.ValueOf(key).Convert(m.Type().Key())
+
+		// map entries aren't addressable, so each is populated in a copy that is stored back
+		entry := m.MapIndex(mapKey)
+		if isPointer && entry.IsValid() && !entry.IsNil() {
+			pc.populateStruct(entry.Elem(), fieldPath(path, key), st)
+			continue
+		}
+		structType := elemType
+		if isPointer {
+			structType = elemType.Elem()
+		}
+		value := reflect.New(structType)
+		if entry.IsValid() && !isPointer {
+			value.Elem().Set(entry)
+		}
+		pc.populateStruct(value.Elem(), fieldPath(path, key), st)
+		if isPointer {
+			m.SetMapIndex(mapKey, value)
+		} else {
+			m.SetMapIndex(mapKey, value.Elem())
+		}
+	}
+}
+
+// assign sets field to a parser's output.  Parsers registered for a named type may return the
+// underlying type (e.g. an int for a `type Port int`), which is converted.
+func assign(field reflect.Value, val any) error {
+	rv := reflect.ValueOf(val)
+	if !rv.IsValid() {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if rv.Type().AssignableTo(field.Type()) {
+		field.Set(rv)
+		return nil
+	}
+	if rv.Kind() == field.Kind() && rv.Type().ConvertibleTo(field.Type()) {
+		field.Set(rv.Convert(field.Type()))
+		return nil
+	}
+	return fmt.Errorf("parser returned %s, which cannot be assigned to %s", rv.Type(), field.Type())
+}
@@ -0,0 +1,47 @@
+package patchpanel
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// BenchRequest resembles a request-binding struct: a handful of scalar fields with hints, and a nested struct
+type BenchRequest struct {
+	ID       UUID          `default:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+	Limit    int           `default:"100"`
+	Offset   int           `default:"0"`
+	Verbose  bool          `default:"yes" boolStyle:"lenient"`
+	Timeout  time.Duration `default:"1d" durationUnits:"extended"`
+	Since    time.Time     `default:"2024-03-01" timeFormat:"RFC3339·DateOnly"`
+	Client   netip.Prefix  `default:"10.0.0.0/8"`
+	Tags     []string      `default:"a,b,c" sep:","`
+	Paginate struct {
+		Size   int    `default:"50"`
+		Cursor string `default:"start"`
+	}
+}
+
+func BenchmarkPopulate(b *testing.B) {
+	pp := New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var dst BenchRequest
+		if err := pp.Populate(&dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPopulateParallel(b *testing.B) {
+	pp := New()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var dst BenchRequest
+			if err := pp.Populate(&dst); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
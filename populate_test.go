@@ -0,0 +1,398 @@
+package patchpanel
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type PopulateDatabase struct {
+	Host string `default:"localhost"`
+	Port int    `default:"5432"`
+}
+
+type PopulateStruct struct {
+	Name     string        `default:"patchpanel"`
+	Timeout  time.Duration `default:"5s"`
+	Start    time.Time     `default:"3:00PM" timeFormat:"Kitchen"`
+	Database PopulateDatabase
+	Cache    *PopulateDatabase
+	Untagged int
+	internal int
+}
+
+type PopulateBroken struct {
+	Port    int           `default:"many"`
+	Timeout time.Duration `default:"soon"`
+	Channel chan int      `default:"1"`
+	Fine    string        `default:"ok"`
+}
+
+type Port int
+
+func TestPatchPanel_Populate(t *testing.T) {
+	pp := New()
+
+	dst := PopulateStruct{Untagged: 7, internal: 3, Cache: &PopulateDatabase{}}
+	if err := pp.Populate(&dst); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+
+	kitchen, _ := time.Parse(time.Kitchen, "3:00PM")
+	want := PopulateStruct{
+		Name:     "patchpanel",
+		Timeout:  5 * time.Second,
+		Start:    kitchen,
+		Database: PopulateDatabase{Host: "localhost", Port: 5432},
+		Cache:    &PopulateDatabase{Host: "localhost", Port: 5432},
+		Untagged: 7,
+		internal: 3,
+	}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("Populate() got = %+v, want %+v", dst, want)
+	}
+}
+
+func TestPatchPanel_PopulateErrors(t *testing.T) {
+	pp := New()
+
+	dst := PopulateBroken{}
+	err := pp.Populate(&dst)
+	if err == nil {
+		t.Fatal("Populate() expected error")
+	}
+
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("Populate() error %v is not a FieldError", err)
+	}
+	if fieldErr.Field != "Port" || fieldErr.Value != "many" {
+		t.Errorf("Populate() first FieldError = %+v", fieldErr)
+	}
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("Populate() error %v does not wrap the parser error", err)
+	}
+
+	var unhandled UnhandledParserTypeError
+	if !errors.As(err, &unhandled) {
+		t.Errorf("Populate() error %v does not report the unhandled chan type", err)
+	}
+
+	// failures don't prevent other fields from being populated
+	if dst.Fine != "ok" {
+		t.Errorf("Populate() Fine = %q, want ok", dst.Fine)
+	}
+}
+
+func TestPatchPanel_PopulateInvalidTarget(t *testing.T) {
+	pp := New(WithDebug(false))
+	var nilPtr *PopulateStruct
+
+	for _, target := range []any{nil, PopulateStruct{}, nilPtr, new(int)} {
+		var targetErr InvalidTargetError
+		if err := pp.Populate(target); !errors.As(err, &targetErr) {
+			t.Errorf("Populate(%T) error = %v, want InvalidTargetError", target, err)
+		}
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	pp := New(
+		WithValueTag("fallback"),
+		WithParser(reflect.TypeOf(Port(0)), func(value string, parserHints map[string]any) (any, error) {
+			// return the underlying type to exercise conversion into the named type
+			return strconv.Atoi(value)
+		}),
+	)
+
+	var dst struct {
+		Port   Port     `fallback:"8080" default:"1"`
+		Events chan int `fallback:"ignored"`
+	}
+	err := pp.Populate(&dst)
+	if dst.Port != 8080 {
+		t.Errorf("Populate() Port = %v, want 8080", dst.Port)
+	}
+	// no parser for chan int has been registered
+	if err == nil {
+		t.Errorf("Populate() expected error for unhandled chan int")
+	}
+}
+
+type ProfileConfig struct {
+	DatabaseAddr string `default:"localhost:5432" default_prod:"db.internal:5432" default_staging:"db.staging:5432"`
+	Debug        bool   `default:"true" default_prod:"false"`
+	Replicas     int    `default:"1" fallback_prod:"3"`
+}
+
+func TestPatchPanel_PopulateProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want ProfileConfig
+	}{
+		{name: "no profile", want: ProfileConfig{DatabaseAddr: "localhost:5432", Debug: true, Replicas: 1}},
+		{name: "dev", opts: []Option{WithProfile("dev")}, want: ProfileConfig{DatabaseAddr: "localhost:5432", Debug: true, Replicas: 1}},
+		{name: "staging", opts: []Option{WithProfile("staging")}, want: ProfileConfig{DatabaseAddr: "db.staging:5432", Debug: true, Replicas: 1}},
+		{name: "prod", opts: []Option{WithProfile("prod")}, want: ProfileConfig{DatabaseAddr: "db.internal:5432", Debug: false, Replicas: 1}},
+		{name: "prod value tag", opts: []Option{WithProfile("prod"), WithValueTag("fallback")}, want: ProfileConfig{Replicas: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conf ProfileConfig
+			if err := New(tt.opts...).Populate(&conf); err != nil {
+				t.Fatalf("Populate() error = %v", err)
+			}
+			if conf != tt.want {
+				t.Errorf("Populate() = %+v, want %+v", conf, tt.want)
+			}
+		})
+	}
+
+	schema, err := New(WithProfile("prod")).Schema(ProfileConfig{})
+	if err != nil || schema.Fields[0].Default != "db.internal:5432" {
+		t.Errorf("Schema() with a profile = %+v, %v", schema, err)
+	}
+}
+
+func TestPatchPanel_PopulateIgnoreUnknownTypes(t *testing.T) {
+	var skipped []FieldError
+	pp := New(WithIgnoreUnknownTypes(func(fieldErr FieldError) {
+		skipped = append(skipped, fieldErr)
+	}))
+
+	dst := PopulateBroken{}
+	err := pp.Populate(&dst)
+
+	// the chan field is skipped with a warning
+	if len(skipped) != 1 || skipped[0].Field != "Channel" {
+		t.Fatalf("Populate() skipped = %v, want Channel", skipped)
+	}
+	if !errors.As(skipped[0], new(UnhandledParserTypeError)) {
+		t.Errorf("Populate() warning %v does not wrap UnhandledParserTypeError", skipped[0])
+	}
+
+	// parse failures are still errors
+	if errors.As(err, new(UnhandledParserTypeError)) {
+		t.Errorf("Populate() error %v reports the skipped field", err)
+	}
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Port" {
+		t.Errorf("Populate() error = %v, want the Port parse failure", err)
+	}
+	if dst.Fine != "ok" {
+		t.Errorf("Populate() Fine = %q, want ok", dst.Fine)
+	}
+
+	// getters remain strict
+	if _, err := pp.GetDefault("Channel", ToReflectType(PopulateBroken{}), nil); err == nil {
+		t.Errorf("GetDefault() expected error for unhandled chan int")
+	}
+}
+
+type PopulateNode struct {
+	Name   string `default:"node"`
+	Weight int    `default:"1"`
+	Next   *PopulateNode
+}
+
+func TestPatchPanel_PopulateCycles(t *testing.T) {
+	pp := New()
+
+	// a -> b -> a, and a -> a
+	a := &PopulateNode{}
+	b := &PopulateNode{Next: a}
+	a.Next = b
+
+	done := make(chan error)
+	go func() { done <- pp.Populate(a) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Populate() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Populate() did not terminate on a pointer cycle")
+	}
+
+	for _, node := range []*PopulateNode{a, b} {
+		if node.Name != "node" || node.Weight != 1 {
+			t.Errorf("Populate() node = %+v", node)
+		}
+	}
+	if a.Next != b || b.Next != a {
+		t.Errorf("Populate() modified the cycle")
+	}
+
+	self := &PopulateNode{}
+	self.Next = self
+	if err := pp.Populate(self); err != nil || self.Name != "node" {
+		t.Errorf("Populate() self reference = %+v, %v", self, err)
+	}
+}
+
+type PopulateDeep struct {
+	Level1 struct {
+		Name   string `default:"one"`
+		Level2 struct {
+			Name string `default:"two"`
+		}
+	}
+}
+
+func TestPatchPanel_PopulateLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		dst     any
+		wantErr bool
+	}{
+		{name: "unlimited", dst: &PopulateDeep{}},
+		{name: "depth within limit", limits: Limits{MaxDepth: 2}, dst: &PopulateDeep{}},
+		{name: "depth exceeded", limits: Limits{MaxDepth: 1}, dst: &PopulateDeep{}, wantErr: true},
+		{name: "fields within limit", limits: Limits{MaxFields: 2}, dst: &PopulateDeep{}},
+		{name: "fields exceeded", limits: Limits{MaxFields: 1}, dst: &PopulateDeep{}, wantErr: true},
+		{name: "bytes within limit", limits: Limits{MaxValueBytes: 6}, dst: &PopulateDeep{}},
+		{name: "bytes exceeded", limits: Limits{MaxValueBytes: 5}, dst: &PopulateDeep{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(WithLimits(tt.limits)).Populate(tt.dst)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Populate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.As(err, new(LimitError)) {
+				t.Errorf("Populate() error = %v, want LimitError", err)
+			}
+		})
+	}
+
+	// the walk stops at the limit
+	dst := PopulateDeep{}
+	_ = New(WithLimits(Limits{MaxFields: 1})).Populate(&dst)
+	if dst.Level1.Name != "one" || dst.Level1.Level2.Name != "" {
+		t.Errorf("Populate() past limit = %+v", dst)
+	}
+}
+
+func TestPatchPanel_PopulateSeq(t *testing.T) {
+	pp := New()
+
+	conf := PopulateStruct{Cache: &PopulateDatabase{}}
+	var paths []string
+	for field, resolved := range pp.PopulateSeq(&conf) {
+		if resolved.Err != nil {
+			t.Errorf("PopulateSeq() field %s error = %v", field.Path, resolved.Err)
+		}
+		paths = append(paths, field.Path)
+	}
+	wantPaths := []string{"Name", "Timeout", "Start", "Database.Host", "Database.Port", "Cache.Host", "Cache.Port"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("PopulateSeq() paths = %v, want %v", paths, wantPaths)
+	}
+	if conf.Database.Port != 5432 || conf.Cache.Host != "localhost" {
+		t.Errorf("PopulateSeq() did not populate nested structs: %+v", conf)
+	}
+
+	// failures are yielded alongside successes
+	var failed []string
+	for field, resolved := range pp.PopulateSeq(&PopulateBroken{}) {
+		if resolved.Err != nil {
+			if !errors.As(resolved.Err, new(FieldError)) {
+				t.Errorf("PopulateSeq() field %s error = %v, want FieldError", field.Path, resolved.Err)
+			}
+			failed = append(failed, field.Path)
+		} else if resolved.Value != "ok" {
+			t.Errorf("PopulateSeq() field %s value = %v", field.Path, resolved.Value)
+		}
+	}
+	if want := []string{"Port", "Timeout", "Channel"}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("PopulateSeq() failed = %v, want %v", failed, want)
+	}
+
+	// stopping early leaves the remaining fields untouched
+	early := PopulateStruct{}
+	for field := range pp.PopulateSeq(&early) {
+		if field.Path == "Timeout" {
+			break
+		}
+	}
+	if early.Timeout != 5*time.Second || !early.Start.IsZero() {
+		t.Errorf("PopulateSeq() after break = %+v, want only Name and Timeout populated", early)
+	}
+
+	for _, resolved := range New(WithDebug(false)).PopulateSeq(early) {
+		if !errors.As(resolved.Err, new(InvalidTargetError)) {
+			t.Errorf("PopulateSeq(struct) error = %v, want InvalidTargetError", resolved.Err)
+		}
+	}
+
+	for field, resolved := range New(WithLimits(Limits{MaxFields: 1})).PopulateSeq(&PopulateDeep{}) {
+		if field.Path != "Level1.Name" && !errors.As(resolved.Err, new(LimitError)) {
+			t.Errorf("PopulateSeq() field %q error = %v, want LimitError", field.Path, resolved.Err)
+		}
+	}
+}
+
+type PopulateWorker struct {
+	Concurrency int           `json:"concurrency" default:"1" min:"1"`
+	Retries     int           `json:"retries" default:"3"`
+	Timeout     time.Duration `json:"timeout" default:"30s"`
+}
+
+type PopulateQueues struct {
+	Workers map[string]PopulateWorker  `json:"workers" defaultKey:"default"`
+	Regions map[string]*PopulateWorker `json:"regions"`
+}
+
+func TestPatchPanel_PopulateMaps(t *testing.T) {
+	pp := New()
+
+	conf := PopulateQueues{
+		Workers: map[string]PopulateWorker{"email": {}, "sms": {Retries: 9}},
+		Regions: map[string]*PopulateWorker{"eu": {}},
+	}
+	if err := pp.Populate(&conf); err != nil {
+		t.Fatalf("Populate() error = %v", err)
+	}
+	wantWorker := PopulateWorker{Concurrency: 1, Retries: 3, Timeout: 30 * time.Second}
+	if conf.Workers["email"] != wantWorker || conf.Workers["sms"] != wantWorker || *conf.Regions["eu"] != wantWorker {
+		t.Errorf("Populate() = %+v, want every entry defaulted", conf)
+	}
+
+	// entries are validated individually, with the key in the path
+	err := pp.PopulateJSON(&PopulateQueues{}, []byte(`{"workers": {"email": {"concurrency": 0}}}`))
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Workers.email.Concurrency" {
+		t.Errorf("PopulateJSON() error = %v, want a failure for Workers.email.Concurrency", err)
+	}
+
+	// the defaultKey entry seeds the others
+	var fromJSON PopulateQueues
+	err = pp.PopulateJSON(&fromJSON, []byte(`{
+		"workers": {
+			"default": {"concurrency": 4, "timeout": "1m"},
+			"email": {"retries": 1},
+			"sms": {"concurrency": 8}
+		},
+		"regions": {"us": {"retries": 0}}
+	}`))
+	if err != nil {
+		t.Fatalf("PopulateJSON() error = %v", err)
+	}
+	want := map[string]PopulateWorker{
+		"default": {Concurrency: 4, Retries: 3, Timeout: time.Minute},
+		"email":   {Concurrency: 4, Retries: 1, Timeout: time.Minute},
+		"sms":     {Concurrency: 8, Retries: 3, Timeout: time.Minute},
+	}
+	if !reflect.DeepEqual(fromJSON.Workers, want) {
+		t.Errorf("PopulateJSON() Workers = %+v, want %+v", fromJSON.Workers, want)
+	}
+	if us := fromJSON.Regions["us"]; us == nil || *us != (PopulateWorker{Concurrency: 1, Retries: 0, Timeout: 30 * time.Second}) {
+		t.Errorf("PopulateJSON() Regions = %+v", fromJSON.Regions)
+	}
+}
@@ -0,0 +1,166 @@
+package patchpanel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Field sources reported by SizeReport and FieldError
+const (
+	SourceTag      = "tag"
+	SourceDeferred = "deferred"
+	SourceNone     = "none"
+	// SourceFile is a value read from a configuration file, e.g. by PopulateJSON
+	SourceFile = "file"
+	// SourceOption is a value set by a ConfigOption, e.g. from SetField
+	SourceOption = "option"
+)
+
+// largestFieldCount is the number of fields listed in SizeReport.Largest
+const largestFieldCount = 10
+
+// SizeReport describes the memory held by a populated struct, to find what is heavy when configuration grows
+type SizeReport struct {
+	// TotalBytes estimates the memory held by the struct, including strings, slices, maps and pointed-to values
+	TotalBytes int
+	// Fields is the number of fields reported on
+	Fields int
+	// FieldsByType counts fields by Go type, e.g. "time.Duration"
+	FieldsByType map[string]int
+	// FieldsBySource counts fields by where their value comes from: SourceTag for fields with a value tag,
+	// SourceDeferred for late-bound fields, and SourceNone for fields patchpanel doesn't populate
+	FieldsBySource map[string]int
+	// Largest lists the heaviest fields, largest first
+	Largest []FieldSize
+}
+
+// FieldSize is the estimated memory held by a single field
+type FieldSize struct {
+	Path  string
+	Type  string
+	Bytes int
+}
+
+// SizeReport measures the struct dst points to, walking fields as Populate does.  Sizes are estimates: they
+// include the backing data of strings, slices and maps, and values reached through pointers are counted once.
+func (pc *PatchPanel) SizeReport(dst any) (SizeReport, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return SizeReport{}, pc.misuse(InvalidTargetError{Msg: fmt.Sprintf("size report target must be a struct or pointer to one, got %T", dst)})
+	}
+
+	report := SizeReport{
+		TotalBytes:     int(rv.Type().Size()),
+		FieldsByType:   make(map[string]int),
+		FieldsBySource: make(map[string]int),
+	}
+	var fields []FieldSize
+	seen := make(map[uintptr]bool)
+	pc.reportStruct(rv, "", seen, &report, &fields)
+
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Bytes > fields[j].Bytes })
+	if len(fields) > largestFieldCount {
+		fields = fields[:largestFieldCount]
+	}
+	report.Largest = fields
+	return report, nil
+}
+
+func (pc *PatchPanel) reportStruct(rv reflect.Value, prefix string, seen map[uintptr]bool, report *SizeReport, fields *[]FieldSize) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sF := rt.Field(i)
+		if !sF.IsExported() && !sF.Anonymous {
+			continue
+		}
+		fieldValue := rv.Field(i)
+		path := fieldPath(prefix, sF.Name)
+
+		if _, ok := pc.parser(sF.Type); !ok {
+			switch {
+			case sF.Type.Kind() == reflect.Struct:
+				pc.reportStruct(fieldValue, path, seen, report, fields)
+				continue
+			case sF.Type.Kind() == reflect.Pointer && sF.Type.Elem().Kind() == reflect.Struct:
+				if !fieldValue.IsNil() && !seen[fieldValue.Pointer()] {
+					seen[fieldValue.Pointer()] = true
+					report.TotalBytes += int(sF.Type.Elem().Size())
+					pc.reportStruct(fieldValue.Elem(), path, seen, report, fields)
+				}
+				continue
+			}
+		}
+
+		source := SourceNone
+		if deferred, _ := isDeferred(sF); deferred {
+			source = SourceDeferred
+		} else if _, ok := pc.lookupValueTag(sF); ok {
+			source = SourceTag
+		}
+
+		// the field's own storage is already part of its parent's size
+		size := indirectSize(fieldValue, seen)
+		report.TotalBytes += size
+		report.Fields++
+		report.FieldsByType[sF.Type.String()]++
+		report.FieldsBySource[source]++
+		*fields = append(*fields, FieldSize{Path: path, Type: sF.Type.String(), Bytes: size + int(sF.Type.Size())})
+	}
+}
+
+// indirectSize estimates the memory referenced by v beyond its own storage
+func indirectSize(v reflect.Value, seen map[uintptr]bool) int {
+	switch v.Kind() {
+	case reflect.String:
+		return v.Len()
+	case reflect.Slice:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		size := v.Cap() * int(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += indirectSize(v.Index(i), seen)
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		size := v.Len() * int(v.Type().Key().Size()+v.Type().Elem().Size())
+		iter := v.MapRange()
+		for iter.Next() {
+			size += indirectSize(iter.Key(), seen) + indirectSize(iter.Value(), seen)
+		}
+		return size
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		return int(v.Type().Elem().Size()) + indirectSize(v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return int(v.Elem().Type().Size()) + indirectSize(v.Elem(), seen)
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < v.NumField(); i++ {
+			size += indirectSize(v.Field(i), seen)
+		}
+		return size
+	case reflect.Array:
+		size := 0
+		for i := 0; i < v.Len(); i++ {
+			size += indirectSize(v.Index(i), seen)
+		}
+		return size
+	}
+	return 0
+}
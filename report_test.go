@@ -0,0 +1,59 @@
+package patchpanel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ReportStruct struct {
+	Name    string        `default:"patchpanel"`
+	Timeout time.Duration `default:"5s"`
+	Blob    []byte
+	Hosts   []string `default:"a·b·c"`
+	Token   string   `deferred:"true"`
+	Nested  struct {
+		Banner string
+	}
+	Shared  *PopulateDatabase
+	Aliased *PopulateDatabase
+}
+
+func TestPatchPanel_SizeReport(t *testing.T) {
+	pp := New()
+	dst := ReportStruct{Shared: &PopulateDatabase{}}
+	dst.Aliased = dst.Shared
+	if err := pp.Populate(&dst); err != nil {
+		t.Fatal(err)
+	}
+	dst.Nested.Banner = strings.Repeat("x", 4096)
+	dst.Blob = make([]byte, 100, 1024)
+
+	report, err := pp.SizeReport(&dst)
+	if err != nil {
+		t.Fatalf("SizeReport() error = %v", err)
+	}
+
+	// Aliased points at the same struct as Shared, so its fields are counted once
+	if report.Fields != 8 {
+		t.Errorf("SizeReport() Fields = %d, want 8", report.Fields)
+	}
+	if report.FieldsByType["string"] != 4 || report.FieldsByType["time.Duration"] != 1 {
+		t.Errorf("SizeReport() FieldsByType = %v", report.FieldsByType)
+	}
+	if report.FieldsBySource[SourceTag] != 5 || report.FieldsBySource[SourceDeferred] != 1 || report.FieldsBySource[SourceNone] != 2 {
+		t.Errorf("SizeReport() FieldsBySource = %v", report.FieldsBySource)
+	}
+	if report.Largest[0].Path != "Nested.Banner" || report.Largest[1].Path != "Blob" {
+		t.Errorf("SizeReport() Largest = %v", report.Largest[:2])
+	}
+	// the banner and the blob's capacity dominate; the shared pointer is counted once
+	if report.TotalBytes < 4096+1024 || report.TotalBytes > 4096+1024+1024 {
+		t.Errorf("SizeReport() TotalBytes = %d", report.TotalBytes)
+	}
+
+	if _, err := New(WithDebug(false)).SizeReport(42); !errors.As(err, new(InvalidTargetError)) {
+		t.Errorf("SizeReport(int) error = %v, want InvalidTargetError", err)
+	}
+}
@@ -0,0 +1,187 @@
+package patchpanel
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Schema describes the fields Populate would visit for a struct type, in a form that can be serialized
+// (e.g. with encoding/json) by one build and compared against another with CompareSchemas.
+type Schema struct {
+	Fields []SchemaField `json:"fields"`
+}
+
+// SchemaField is a single field of a Schema
+type SchemaField struct {
+	// Path is the dotted path of the field from the root struct, as reported in FieldError
+	Path string `json:"path"`
+	// Type is the field's Go type, e.g. "time.Duration"
+	Type string `json:"type"`
+	// Default is the field's value tag, when HasDefault is set
+	Default    string `json:"default,omitempty"`
+	HasDefault bool   `json:"hasDefault"`
+	// DataClasses lists the field's data classifications from its dataclass tag, e.g.
+	// `dataclass:"pii·confidential"`, for compliance tooling
+	DataClasses []string `json:"dataClasses,omitempty"`
+}
+
+// DataClassPII and DataClassSecret are the data classes whose values are redacted from FieldErrors, see
+// RedactedValue
+const (
+	DataClassPII    = "pii"
+	DataClassSecret = "secret"
+)
+
+// dataClasses lists the classes of a field's dataclass tag
+func (pc *PatchPanel) dataClasses(sF reflect.StructField) []string {
+	return splitTokens(sF.Tag.Get("dataclass"), pc.tokenSeparator)
+}
+
+// SchemaChangeKind classifies a difference between two schemas
+type SchemaChangeKind string
+
+const (
+	FieldAdded     SchemaChangeKind = "added"
+	FieldRemoved   SchemaChangeKind = "removed"
+	TypeChanged    SchemaChangeKind = "typeChanged"
+	DefaultChanged SchemaChangeKind = "defaultChanged"
+	// DataClassChanged reports a field whose dataclass tag changed, e.g. one newly classified as pii
+	DataClassChanged SchemaChangeKind = "dataClassChanged"
+)
+
+// SchemaChange is a single difference between two schemas.  Old and New hold the type or default being
+// compared, and are empty for fields that are missing on that side.
+type SchemaChange struct {
+	Kind SchemaChangeKind `json:"kind"`
+	Path string           `json:"path"`
+	Old  string           `json:"old,omitempty"`
+	New  string           `json:"new,omitempty"`
+}
+
+func (sc SchemaChange) String() string {
+	switch sc.Kind {
+	case FieldAdded:
+		return fmt.Sprintf("%s: added (%s)", sc.Path, sc.New)
+	case FieldRemoved:
+		return fmt.Sprintf("%s: removed (%s)", sc.Path, sc.Old)
+	default:
+		return fmt.Sprintf("%s: %s %q -> %q", sc.Path, sc.Kind, sc.Old, sc.New)
+	}
+}
+
+// SchemaEntryKey stands in for the keys of a map of structs in schema paths, e.g. Workers.*.Retries for the
+// Retries field of every entry of a map[string]Worker
+const SchemaEntryKey = "*"
+
+// Schema describes the struct v, which may be a struct, a pointer to one, or a reflect.Type of either.
+// Fields are walked as Populate walks them, reading defaults from the panel's value tag; the fields of the
+// entries of a map of structs are listed once, under SchemaEntryKey.
+func (pc *PatchPanel) Schema(v any) (Schema, error) {
+	rt, ok := v.(reflect.Type)
+	if !ok {
+		rt = reflect.TypeOf(v)
+	}
+	if rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return Schema{}, pc.misuse(InvalidTargetError{Msg: fmt.Sprintf("schema target must be a struct, got %v", rt)})
+	}
+
+	var schema Schema
+	pc.schemaFields(rt, "", map[reflect.Type]bool{}, &schema.Fields)
+	return schema, nil
+}
+
+// schemaFields appends the fields of rt to fields.  seen holds the struct types on the current path so that
+// self-referencing types terminate.
+func (pc *PatchPanel) schemaFields(rt reflect.Type, prefix string, seen map[reflect.Type]bool, fields *[]SchemaField) {
+	seen[rt] = true
+	defer delete(seen, rt)
+
+	for i := 0; i < rt.NumField(); i++ {
+		sF := rt.Field(i)
+		if !sF.IsExported() && !sF.Anonymous {
+			continue
+		}
+		path := sF.Name
+		if prefix != "" {
+			path = prefix + "." + sF.Name
+		}
+
+		if _, ok := pc.parser(sF.Type); !ok {
+			nested := sF.Type
+			if nested.Kind() == reflect.Pointer {
+				nested = nested.Elem()
+			}
+			if nested.Kind() == reflect.Struct {
+				if !seen[nested] {
+					pc.schemaFields(nested, path, seen, fields)
+				}
+				continue
+			}
+			if pc.isStructMap(sF.Type) {
+				entry := sF.Type.Elem()
+				if entry.Kind() == reflect.Pointer {
+					entry = entry.Elem()
+				}
+				if !seen[entry] {
+					pc.schemaFields(entry, fieldPath(path, SchemaEntryKey), seen, fields)
+				}
+				continue
+			}
+		}
+
+		raw, hasDefault := pc.lookupValueTag(sF)
+		field := SchemaField{Path: path, Type: sF.Type.String(), Default: raw, HasDefault: hasDefault}
+		field.DataClasses = pc.dataClasses(sF)
+		*fields = append(*fields, field)
+	}
+}
+
+// CompareSchemas reports the differences between the schema of an old and a new build, so that a rollout in
+// which both versions read the same configuration can be checked for removed fields, type changes, and
+// changed defaults or data classes.  Changes are ordered by path.
+func CompareSchemas(oldSchema, newSchema Schema) []SchemaChange {
+	oldFields := make(map[string]SchemaField, len(oldSchema.Fields))
+	for _, f := range oldSchema.Fields {
+		oldFields[f.Path] = f
+	}
+	newFields := make(map[string]SchemaField, len(newSchema.Fields))
+	for _, f := range newSchema.Fields {
+		newFields[f.Path] = f
+	}
+
+	var changes []SchemaChange
+	for path, o := range oldFields {
+		n, ok := newFields[path]
+		switch {
+		case !ok:
+			changes = append(changes, SchemaChange{Kind: FieldRemoved, Path: path, Old: o.Type})
+		case o.Type != n.Type:
+			changes = append(changes, SchemaChange{Kind: TypeChanged, Path: path, Old: o.Type, New: n.Type})
+		case o.HasDefault != n.HasDefault || o.Default != n.Default:
+			changes = append(changes, SchemaChange{Kind: DefaultChanged, Path: path, Old: o.Default, New: n.Default})
+		}
+		if ok && !slices.Equal(o.DataClasses, n.DataClasses) {
+			changes = append(changes, SchemaChange{Kind: DataClassChanged, Path: path,
+				Old: strings.Join(o.DataClasses, ", "), New: strings.Join(n.DataClasses, ", ")})
+		}
+	}
+	for path, n := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			changes = append(changes, SchemaChange{Kind: FieldAdded, Path: path, New: n.Type})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Path != changes[j].Path {
+			return changes[i].Path < changes[j].Path
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
@@ -0,0 +1,55 @@
+package patchpanel
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SchemaFlag is the name of the flag added by AddSchemaFlag
+const SchemaFlag = "config-schema"
+
+// schemaExit ends the process once the schema flag has printed the schema
+var schemaExit = os.Exit
+
+// WriteSchema writes the Schema of v as indented JSON.  Run at build time, e.g. from a go:generate program, its
+// output can be embedded in the binary and served with AddSchemaFlag:
+//
+//	//go:generate go run ./internal/genschema -o config.schema.json
+//	//go:embed config.schema.json
+//	var configSchema []byte
+func (pc *PatchPanel) WriteSchema(w io.Writer, v any) error {
+	schema, err := pc.Schema(v)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(schema)
+}
+
+// ParseSchema reads a schema written by WriteSchema, e.g. one embedded in the binary, for use with CompareSchemas
+func ParseSchema(data []byte) (Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return Schema{}, fmt.Errorf("decoding schema: %w", err)
+	}
+	return schema, nil
+}
+
+// AddSchemaFlag adds a --config-schema flag to fs that writes schemaJSON to fs.Output() and exits with status 0,
+// so operators can ask any deployed binary what configuration it accepts:
+//
+//	patchpanel.AddSchemaFlag(flag.CommandLine, configSchema)
+//	flag.Parse()
+func AddSchemaFlag(fs *flag.FlagSet, schemaJSON []byte) {
+	fs.BoolFunc(SchemaFlag, "print the configuration schema as JSON and exit", func(string) error {
+		if _, err := fs.Output().Write(schemaJSON); err != nil {
+			return err
+		}
+		schemaExit(0)
+		return nil
+	})
+}
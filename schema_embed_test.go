@@ -0,0 +1,52 @@
+package patchpanel
+
+import (
+	"bytes"
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestPatchPanel_WriteSchema(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New().WriteSchema(&buf, SchemaV2{}); err != nil {
+		t.Fatalf("WriteSchema() error = %v", err)
+	}
+	parsed, err := ParseSchema(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	want, _ := New().Schema(SchemaV2{})
+	if !reflect.DeepEqual(parsed, want) {
+		t.Errorf("ParseSchema(WriteSchema()) = %+v, want %+v", parsed, want)
+	}
+
+	if err := New(WithDebug(false)).WriteSchema(&buf, 42); err == nil {
+		t.Errorf("WriteSchema() of a non-struct succeeded")
+	}
+	if _, err := ParseSchema([]byte("{")); err == nil {
+		t.Errorf("ParseSchema() of invalid JSON succeeded")
+	}
+}
+
+func TestAddSchemaFlag(t *testing.T) {
+	exitCode := -1
+	defer func(exit func(int)) { schemaExit = exit }(schemaExit)
+	schemaExit = func(code int) { exitCode = code }
+
+	schemaJSON := []byte(`{"fields":[]}`)
+	var out bytes.Buffer
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&out)
+	AddSchemaFlag(fs, schemaJSON)
+
+	if err := fs.Parse(nil); err != nil || exitCode != -1 || out.Len() != 0 {
+		t.Fatalf("Parse() without the flag = %v, exit %d, output %q", err, exitCode, out.String())
+	}
+	if err := fs.Parse([]string{"--" + SchemaFlag}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if exitCode != 0 || out.String() != string(schemaJSON) {
+		t.Errorf("--%s exited %d with output %q", SchemaFlag, exitCode, out.String())
+	}
+}
@@ -0,0 +1,107 @@
+package patchpanel
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type SchemaV1 struct {
+	Name     string        `default:"app"`
+	Email    string        `dataclass:"pii"`
+	Timeout  time.Duration `default:"5s"`
+	Port     int           `default:"8080"`
+	Legacy   bool          `default:"true"`
+	Database PopulateDatabase
+	Parent   *SchemaV1
+}
+
+type SchemaV2 struct {
+	Name     string        `default:"app"`
+	Timeout  time.Duration `default:"10s"`
+	Port     string        `default:"8080"`
+	Email    string        `dataclass:"pii · confidential"`
+	Database PopulateDatabase
+	Replicas int
+}
+
+func TestPatchPanel_Schema(t *testing.T) {
+	pp := New()
+
+	schema, err := pp.Schema(&SchemaV1{})
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	var paths []string
+	for _, f := range schema.Fields {
+		paths = append(paths, f.Path)
+	}
+	want := []string{"Name", "Email", "Timeout", "Port", "Legacy", "Database.Host", "Database.Port"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Schema() paths = %v, want %v", paths, want)
+	}
+	if f := schema.Fields[1]; !reflect.DeepEqual(f.DataClasses, []string{"pii"}) {
+		t.Errorf("Schema() Email = %+v", f)
+	}
+	if f := schema.Fields[2]; f.Type != "time.Duration" || f.Default != "5s" || !f.HasDefault {
+		t.Errorf("Schema() Timeout = %+v", f)
+	}
+
+	// map of struct entries are described once, under SchemaEntryKey
+	queues, err := pp.Schema(PopulateQueues{})
+	if err != nil {
+		t.Fatalf("Schema(PopulateQueues) error = %v", err)
+	}
+	paths = nil
+	for _, f := range queues.Fields {
+		paths = append(paths, f.Path)
+	}
+	want = []string{
+		"Workers.*.Concurrency", "Workers.*.Retries", "Workers.*.Timeout",
+		"Regions.*.Concurrency", "Regions.*.Retries", "Regions.*.Timeout",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Schema(PopulateQueues) paths = %v, want %v", paths, want)
+	}
+	if f := queues.Fields[1]; f.Default != "3" || f.Type != "int" {
+		t.Errorf("Schema(PopulateQueues) Workers.*.Retries = %+v", f)
+	}
+
+	if _, err := New(WithDebug(false)).Schema(42); !errors.As(err, new(InvalidTargetError)) {
+		t.Errorf("Schema(int) error = %v, want InvalidTargetError", err)
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	pp := New()
+	oldSchema, _ := pp.Schema(SchemaV1{})
+	newSchema, _ := pp.Schema(reflect.TypeOf(SchemaV2{}))
+
+	// schemas survive a round trip through JSON, as when produced by another binary
+	encoded, err := json.Marshal(oldSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Schema
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	got := CompareSchemas(decoded, newSchema)
+	want := []SchemaChange{
+		{Kind: DataClassChanged, Path: "Email", Old: "pii", New: "pii, confidential"},
+		{Kind: FieldRemoved, Path: "Legacy", Old: "bool"},
+		{Kind: TypeChanged, Path: "Port", Old: "int", New: "string"},
+		{Kind: FieldAdded, Path: "Replicas", New: "int"},
+		{Kind: DefaultChanged, Path: "Timeout", Old: "5s", New: "10s"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareSchemas() = %v, want %v", got, want)
+	}
+
+	if changes := CompareSchemas(newSchema, newSchema); len(changes) != 0 {
+		t.Errorf("CompareSchemas() of identical schemas = %v", changes)
+	}
+}
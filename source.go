@@ -0,0 +1,295 @@
+package patchpanel
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Source supplies raw field values to PopulateFrom, as one layer of a precedence chain such as flags, then the
+// environment, then a configuration file.
+type Source interface {
+	// Name identifies the source in FieldError.Source and PopulateSeq, e.g. "env"
+	Name() string
+	// Lookup returns the raw value of the field sF at the dotted path, and whether the source has one.  Values
+	// are run through the field's parser and hints, as value tags are.
+	Lookup(sF reflect.StructField, path string) (string, bool, error)
+}
+
+// Sources of the built-in Source implementations
+const (
+	SourceEnv        = "env"
+	SourceFlag       = "flag"
+	SourceSecret     = "secret"
+	SourceLabel      = "label"
+	SourceCredential = "credential"
+	SourceMetadata   = "metadata"
+)
+
+// NameMapper bridges field paths to the names a source keys its values by, such as secret file names: it maps
+// the field sF at the dotted path (e.g. "Database.Password") to a name, or to "" to leave the field to later
+// sources
+type NameMapper func(sF reflect.StructField, path string) string
+
+// SnakeCaseNames is the default NameMapper of name-keyed sources, lowercasing the path with underscores between
+// words and segments, e.g. "Database.PasswordURL" becomes "database_password_url"
+func SnakeCaseNames(_ reflect.StructField, path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '.' {
+			b.WriteByte('_')
+			continue
+		}
+		if isUpper(c) && i > 0 && path[i-1] != '.' {
+			// a new word starts after a lowercase letter or digit, or at the last capital of an acronym
+			prev := path[i-1]
+			if !isUpper(prev) || (i+1 < len(path) && isLower(path[i+1])) {
+				b.WriteByte('_')
+			}
+		}
+		if isUpper(c) {
+			c += 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func isUpper(c byte) bool { return 'A' <= c && c <= 'Z' }
+func isLower(c byte) bool { return 'a' <= c && c <= 'z' }
+
+// TagNames is a NameMapper reading each field's name from tag, e.g. TagNames("secret") for `secret:"db_password"`;
+// untagged fields are left to later sources
+func TagNames(tag string) NameMapper {
+	return func(sF reflect.StructField, _ string) string {
+		return sF.Tag.Get(tag)
+	}
+}
+
+// mappedSource is a Source over a lookup of names produced by a NameMapper
+type mappedSource struct {
+	name   string
+	mapper NameMapper
+	lookup func(name string) (string, bool, error)
+}
+
+func (ms mappedSource) Name() string {
+	return ms.name
+}
+
+func (ms mappedSource) Lookup(sF reflect.StructField, path string) (string, bool, error) {
+	name := ms.mapper(sF, path)
+	if name == "" {
+		return "", false, nil
+	}
+	return ms.lookup(name)
+}
+
+// binder is implemented by sources that index themselves against the populated type before lookups, such as
+// documents, which map struct paths to objects and provide the entries of maps of structs
+type binder interface {
+	bind(pc *PatchPanel, rt reflect.Type) (Source, *documentIndex)
+}
+
+// PopulateFrom fills dst from an ordered chain of sources: each field takes its value from the first source that
+// has one, and otherwise from its value tag, so defaults stay the last layer:
+//
+//	file, err := patchpanel.FileSource("config.toml")
+//	...
+//	err = pc.PopulateFrom(&conf, patchpanel.FlagSource(flag.CommandLine), patchpanel.EnvSource(), file)
+//
+// Failures are reported as with Populate, with FieldError.Source naming the source a failing value came from.
+// Maps of structs get the entries of every document source.
+func (pc *PatchPanel) PopulateFrom(dst any, sources ...Source) error {
+	rv, err := populateTarget(dst)
+	if err != nil {
+		return pc.misuse(err)
+	}
+
+	bound := make([]Source, len(sources))
+	var indexes []*documentIndex
+	for i, src := range sources {
+		if src == nil {
+			return pc.misuse(errors.New("nil Source passed to PopulateFrom"))
+		}
+		bound[i] = src
+		if b, ok := src.(binder); ok {
+			var index *documentIndex
+			bound[i], index = b.bind(pc, rv.Type())
+			indexes = append(indexes, index)
+		}
+	}
+
+	var st *populateState
+	st = pc.newPopulateState(func(sF reflect.StructField, prefix string) (string, bool, error) {
+		path := fieldPath(prefix, sF.Name)
+		for _, src := range bound {
+			raw, ok, err := src.Lookup(sF, path)
+			if err != nil || ok {
+				st.source = src.Name()
+				return raw, ok, err
+			}
+		}
+		st.source = SourceTag
+		return pc.tagValue(sF, prefix)
+	})
+	defer st.release()
+	if len(indexes) > 0 {
+		st.entryKeys = func(path string) []string {
+			var keys []string
+			for _, index := range indexes {
+				keys = append(keys, index.entryKeys[path]...)
+			}
+			return keys
+		}
+		st.hasObject = func(path string) bool {
+			for _, index := range indexes {
+				if index.objects[path] != nil {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	for _, index := range indexes {
+		st.errs = append(st.errs, index.errs...)
+	}
+	pc.populateStruct(rv, "", st)
+	return errors.Join(st.errs...)
+}
+
+// lookupSource is a Source over a function of a field's tag
+type lookupSource struct {
+	name   string
+	tag    string
+	lookup func(key string) (string, bool)
+}
+
+func (ls lookupSource) Name() string {
+	return ls.name
+}
+
+func (ls lookupSource) Lookup(sF reflect.StructField, _ string) (string, bool, error) {
+	key := sF.Tag.Get(ls.tag)
+	if key == "" {
+		return "", false, nil
+	}
+	raw, ok := ls.lookup(key)
+	return raw, ok, nil
+}
+
+// EnvSource is a Source reading each field tagged `env:"NAME"` from the process environment
+func EnvSource() Source {
+	return lookupSource{name: SourceEnv, tag: EnvTag, lookup: os.LookupEnv}
+}
+
+// DotenvSource is a Source reading each field tagged `env:"NAME"` from a .env file, as parsed by ParseDotenv,
+// without touching the process environment
+func DotenvSource(data []byte) (Source, error) {
+	env, err := ParseDotenv(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding .env file: %w", err)
+	}
+	return lookupSource{name: SourceFile, tag: EnvTag, lookup: func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}}, nil
+}
+
+// FlagTag names the tag holding a field's command line flag, e.g. `flag:"port"`
+const FlagTag = "flag"
+
+// FlagSource is a Source reading each field tagged `flag:"name"` from fs, if the flag was set on the command
+// line; flags left at their defaults fall through to later sources.  fs must already be parsed.
+func FlagSource(fs *flag.FlagSet) Source {
+	set := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = f.Value.String()
+	})
+	return lookupSource{name: SourceFlag, tag: FlagTag, lookup: func(key string) (string, bool) {
+		value, ok := set[key]
+		return value, ok
+	}}
+}
+
+// documentSource is a Source over a decoded configuration document of nested map[string]any objects.  It is
+// bound to the populated type before use, as its lookups go through the document's objects by struct path.
+type documentSource struct {
+	name      string
+	doc       map[string]any
+	formatTag string
+	// pc and index are set once bound
+	pc    *PatchPanel
+	index *documentIndex
+}
+
+// DocumentSource is a Source over a decoded document, e.g. from encoding/json into a map[string]any, whose keys
+// are matched as PopulateJSON matches them, reading names from formatTag (e.g. "json") after ConfigTag
+func DocumentSource(name string, doc map[string]any, formatTag string) Source {
+	return &documentSource{name: name, doc: doc, formatTag: formatTag}
+}
+
+func (ds *documentSource) Name() string {
+	return ds.name
+}
+
+func (ds *documentSource) bind(pc *PatchPanel, rt reflect.Type) (Source, *documentIndex) {
+	index := &documentIndex{
+		pc:        pc,
+		formatTag: ds.formatTag,
+		source:    ds.name,
+		objects:   make(map[string]map[string]any),
+		entryKeys: make(map[string][]string),
+		seen:      make(map[reflect.Type]bool),
+	}
+	index.add(rt, "", ds.doc)
+	return &documentSource{name: ds.name, doc: ds.doc, formatTag: ds.formatTag, pc: pc, index: index}, index
+}
+
+func (ds *documentSource) Lookup(sF reflect.StructField, path string) (string, bool, error) {
+	if ds.index == nil {
+		return "", false, nil
+	}
+	prefix := strings.TrimSuffix(strings.TrimSuffix(path, sF.Name), ".")
+	value, ok := lookupDocumentKey(ds.index.objects[prefix], documentKey(sF, ds.formatTag))
+	if !ok || value == nil {
+		return "", false, nil
+	}
+	// a properties key with both a value and nested keys reads as its value
+	if obj, isObject := value.(map[string]any); isObject && ds.formatTag == "properties" {
+		if flat, hasValue := obj[PropertiesValueKey]; hasValue {
+			value = flat
+		}
+	}
+	raw, err := documentValue(value, ds.pc.separator(fieldHints(sF)))
+	return raw, true, err
+}
+
+// documentFormats decodes configuration files by extension, naming the format tag each is keyed with
+var documentFormats = map[string]struct {
+	formatTag string
+	decode    func(data []byte) (map[string]any, error)
+}{
+	".json":       {"json", decodeJSON},
+	".toml":       {"toml", func(data []byte) (map[string]any, error) { return decodeTOML(string(data)) }},
+	".ini":        {"ini", func(data []byte) (map[string]any, error) { return decodeINI(string(data)) }},
+	".hcl":        {"hcl", func(data []byte) (map[string]any, error) { return decodeHCL(string(data)) }},
+	".properties": {"properties", func(data []byte) (map[string]any, error) { return decodeProperties(string(data)) }},
+	".xml":        {"xml", decodeXML},
+}
+
+// FileSource reads the configuration file at path into a Source, choosing the format by extension: .json, .toml,
+// .ini, .hcl, .properties or .xml.  Files listed under its IncludeKey are loaded beneath it.  Values it supplies
+// are reported as SourceFile.
+func FileSource(path string) (Source, error) {
+	doc := make(map[string]any)
+	formatTag, err := loadDocument(path, nil, doc, make(map[string]*MergedValue))
+	if err != nil {
+		return nil, err
+	}
+	return DocumentSource(SourceFile, doc, formatTag), nil
+}
@@ -0,0 +1,128 @@
+package patchpanel
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type LayeredConfig struct {
+	Port    int                       `flag:"port" env:"PATCHPANEL_TEST_PORT" json:"port" default:"80"`
+	Host    string                    `flag:"host" env:"PATCHPANEL_TEST_HOST" json:"host" default:"localhost"`
+	Timeout time.Duration             `env:"PATCHPANEL_TEST_TIMEOUT" json:"timeout" default:"5s"`
+	Level   string                    `json:"level" default:"info"`
+	Region  string                    `default:"us-east-1"`
+	Workers map[string]PopulateWorker `json:"workers"`
+}
+
+func TestPatchPanel_PopulateFrom(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("port", 0, "")
+	fs.String("host", "flag-default", "")
+	if err := fs.Parse([]string{"-port", "9090"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATCHPANEL_TEST_PORT", "7070")
+	t.Setenv("PATCHPANEL_TEST_HOST", "env-host")
+	doc := DocumentSource("primary", map[string]any{
+		"port": "6060", "host": "doc-host", "timeout": "10s", "level": "debug",
+		"workers": map[string]any{"email": map[string]any{"retries": "1"}},
+	}, "json")
+	fallback := DocumentSource("fallback", map[string]any{
+		"level":   "warn",
+		"workers": map[string]any{"sms": map[string]any{"retries": "2"}},
+	}, "json")
+
+	var conf LayeredConfig
+	if err := New().PopulateFrom(&conf, FlagSource(fs), EnvSource(), doc, fallback); err != nil {
+		t.Fatalf("PopulateFrom() error = %v", err)
+	}
+	want := LayeredConfig{
+		Port:    9090,
+		Host:    "env-host",
+		Timeout: 10 * time.Second,
+		Level:   "debug",
+		Region:  "us-east-1",
+		Workers: map[string]PopulateWorker{
+			"email": {Concurrency: 1, Retries: 1, Timeout: 30 * time.Second},
+			"sms":   {Concurrency: 1, Retries: 2, Timeout: 30 * time.Second},
+		},
+	}
+	if !reflect.DeepEqual(conf, want) {
+		t.Errorf("PopulateFrom() = %+v, want %+v", conf, want)
+	}
+
+	// the source of a failing value is reported
+	t.Setenv("PATCHPANEL_TEST_TIMEOUT", "soon")
+	err := New().PopulateFrom(&conf, EnvSource(), doc)
+	var fieldErr FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Timeout" || fieldErr.Source != SourceEnv {
+		t.Errorf("PopulateFrom() error = %#v, want an env FieldError for Timeout", err)
+	}
+
+	// without sources, value tags are used as by Populate
+	var defaults LayeredConfig
+	if err := New().PopulateFrom(&defaults); err != nil || defaults.Port != 80 || defaults.Level != "info" {
+		t.Errorf("PopulateFrom() without sources = %+v, %v", defaults, err)
+	}
+
+	if err := New(WithDebug(false)).PopulateFrom(&conf, nil); err == nil {
+		t.Errorf("PopulateFrom() with a nil source succeeded")
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.json":       `{"level": "json"}`,
+		"config.toml":       `level = "toml"`,
+		"config.ini":        "level = ini",
+		"config.hcl":        `level = "hcl"`,
+		"config.properties": "level=properties",
+		"config.XML":        "<config><level>xml</level></config>",
+		"invalid.json":      "{",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "config.json", want: "json"},
+		{name: "config.toml", want: "toml"},
+		{name: "config.ini", want: "ini"},
+		{name: "config.hcl", want: "hcl"},
+		{name: "config.properties", want: "properties"},
+		{name: "config.XML", want: "xml"},
+		{name: "config.yaml", wantErr: true},
+		{name: "invalid.json", wantErr: true},
+		{name: "missing.json", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := FileSource(filepath.Join(dir, tt.name))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FileSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var conf LayeredConfig
+			if err := New().PopulateFrom(&conf, src); err != nil || conf.Level != tt.want {
+				t.Errorf("PopulateFrom(FileSource()) Level = %q, %v", conf.Level, err)
+			}
+			if src.Name() != SourceFile {
+				t.Errorf("FileSource().Name() = %q", src.Name())
+			}
+		})
+	}
+}
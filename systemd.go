@@ -0,0 +1,117 @@
+package patchpanel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// systemd passes activated sockets starting at this descriptor
+const listenFDsStart = 3
+
+// ErrNoSystemdCredentials is returned when the process was not started with systemd credentials
+var ErrNoSystemdCredentials = errors.New("CREDENTIALS_DIRECTORY is not set")
+
+// systemdListenFDs reads the descriptors and names passed by systemd socket activation without taking
+// ownership of them
+func systemdListenFDs() ([]int, []string, error) {
+	pidStr, ok := os.LookupEnv("LISTEN_PID")
+	if !ok {
+		return nil, nil, nil
+	}
+	pid, err := strconv.Atoi(pidStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid LISTEN_PID %q", pidStr)
+	}
+	// the variables were meant for another process, e.g. our parent
+	if pid != os.Getpid() {
+		return nil, nil, nil
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 0 {
+		return nil, nil, fmt.Errorf("invalid LISTEN_FDS %q", os.Getenv("LISTEN_FDS"))
+	}
+
+	var fdNames []string
+	if joined := os.Getenv("LISTEN_FDNAMES"); joined != "" {
+		fdNames = strings.Split(joined, ":")
+	}
+
+	fds := make([]int, 0, count)
+	names := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		fd := listenFDsStart + i
+		name := "fd:" + strconv.Itoa(fd)
+		if i < len(fdNames) && fdNames[i] != "" {
+			name = fdNames[i]
+		}
+		fds = append(fds, fd)
+		names = append(names, name)
+	}
+	return fds, names, nil
+}
+
+// SystemdListenFiles returns the sockets passed by systemd socket activation (LISTEN_PID, LISTEN_FDS and
+// LISTEN_FDNAMES).  Each file is named after its FileDescriptorName, or "fd:<n>" when systemd supplied no name.
+// No files, and no error, are returned when the process was not socket activated.
+//
+// The caller owns the returned files; closing one, or letting it be garbage collected, closes the socket.
+func SystemdListenFiles() ([]*os.File, error) {
+	fds, names, err := systemdListenFDs()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]*os.File, 0, len(fds))
+	for i, fd := range fds {
+		files = append(files, os.NewFile(uintptr(fd), names[i]))
+	}
+	return files, nil
+}
+
+// systemdListenFD finds the descriptor systemd passed under name
+func systemdListenFD(name string) (int, error) {
+	fds, names, err := systemdListenFDs()
+	if err != nil {
+		return 0, err
+	}
+	for i, fdName := range names {
+		if fdName == name {
+			return fds[i], nil
+		}
+	}
+	return 0, fmt.Errorf("no socket named %q was passed by systemd", name)
+}
+
+// SystemdCredential reads the credential name from the directory systemd provides in $CREDENTIALS_DIRECTORY
+// (LoadCredential=, SetCredential= and friends).  A single trailing newline is removed.
+func SystemdCredential(name string) (string, error) {
+	dir, ok := os.LookupEnv("CREDENTIALS_DIRECTORY")
+	if !ok || dir == "" {
+		return "", ErrNoSystemdCredentials
+	}
+	if name == "" || strings.ContainsRune(name, filepath.Separator) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid credential name %q", name)
+	}
+	contents, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(contents), "\n"), nil
+}
+
+// SystemdCredentialsSource is a Source reading each field from the credential in $CREDENTIALS_DIRECTORY named by
+// mapper, e.g. TagNames("credential") for `credential:"db-password"`; a nil mapper uses SnakeCaseNames.  Outside of
+// systemd, or without credentials, it has no values, so the same chain works in development.  Values are reported
+// as SourceCredential.
+func SystemdCredentialsSource(mapper NameMapper) Source {
+	dir := os.Getenv("CREDENTIALS_DIRECTORY")
+	if dir == "" {
+		return mappedSource{name: SourceCredential, mapper: func(reflect.StructField, string) string { return "" }}
+	}
+	return dirSource(SourceCredential, dir, mapper)
+}
@@ -0,0 +1,94 @@
+package patchpanel
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// the test process's own descriptors 3 and up are not handed to os.NewFile, whose finalizer would close them
+func TestSystemdListenFDs(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "2")
+	t.Setenv("LISTEN_FDNAMES", "http:")
+
+	fds, names, err := systemdListenFDs()
+	if err != nil {
+		t.Fatalf("systemdListenFDs() error = %v", err)
+	}
+	if !reflect.DeepEqual(fds, []int{3, 4}) || !reflect.DeepEqual(names, []string{"http", "fd:4"}) {
+		t.Fatalf("systemdListenFDs() = %v, %v", fds, names)
+	}
+
+	if fd, err := systemdListenFD("http"); err != nil || fd != 3 {
+		t.Errorf("systemdListenFD(http) = %d, %v", fd, err)
+	}
+	if _, err := systemdListenFD("grpc"); err == nil {
+		t.Errorf("systemdListenFD(grpc) expected error")
+	}
+
+	// variables addressed to another process are ignored
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	if files, err := SystemdListenFiles(); err != nil || len(files) != 0 {
+		t.Errorf("SystemdListenFiles() for another pid = %v, %v", files, err)
+	}
+
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "many")
+	if _, err := SystemdListenFiles(); err == nil {
+		t.Errorf("SystemdListenFiles() expected error for invalid LISTEN_FDS")
+	}
+}
+
+func TestSystemdCredential(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db-password"), []byte("hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CREDENTIALS_DIRECTORY", "")
+	if _, err := SystemdCredential("db-password"); !errors.Is(err, ErrNoSystemdCredentials) {
+		t.Errorf("SystemdCredential() error = %v, want ErrNoSystemdCredentials", err)
+	}
+
+	t.Setenv("CREDENTIALS_DIRECTORY", dir)
+	if got, err := SystemdCredential("db-password"); err != nil || got != "hunter2" {
+		t.Errorf("SystemdCredential() = %q, %v", got, err)
+	}
+	if _, err := SystemdCredential("missing"); err == nil {
+		t.Errorf("SystemdCredential(missing) expected error")
+	}
+	if _, err := SystemdCredential("../etc/passwd"); err == nil {
+		t.Errorf("SystemdCredential() expected error for a path")
+	}
+}
+
+func TestSystemdCredentialsSource(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db-password"), []byte("hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	type CredentialConfig struct {
+		Password string `credential:"db-password" default:"dev"`
+		Token    string `credential:"token"`
+	}
+
+	t.Setenv("CREDENTIALS_DIRECTORY", dir)
+	var conf CredentialConfig
+	if err := New().PopulateFrom(&conf, SystemdCredentialsSource(TagNames("credential"))); err != nil {
+		t.Fatalf("PopulateFrom(SystemdCredentialsSource()) error = %v", err)
+	}
+	if conf.Password != "hunter2" || conf.Token != "" {
+		t.Errorf("PopulateFrom(SystemdCredentialsSource()) = %+v", conf)
+	}
+
+	// without credentials, fields fall through to their defaults
+	t.Setenv("CREDENTIALS_DIRECTORY", "")
+	conf = CredentialConfig{}
+	if err := New().PopulateFrom(&conf, SystemdCredentialsSource(TagNames("credential"))); err != nil || conf.Password != "dev" {
+		t.Errorf("PopulateFrom(SystemdCredentialsSource()) without credentials = %+v, %v", conf, err)
+	}
+}
@@ -0,0 +1,332 @@
+package patchpanel
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// validate checks a parsed value of type typ against the constraint hints on its field.  Constraints on slice
+// types apply to each element, except for the length constraints, which apply to the slice itself.
+func (pc *PatchPanel) validate(val any, typ reflect.Type, parserHints Hints) error {
+	if parserHints.Has("oneof") {
+		if err := pc.validateOneOf(val, typ, parserHints); err != nil {
+			return err
+		}
+	}
+	if parserHints.Has("pattern") {
+		if err := pc.validatePattern(val, typ, parserHints); err != nil {
+			return err
+		}
+	}
+	for _, bound := range []string{"min", "max"} {
+		if parserHints.Has(bound) {
+			if err := pc.validateBound(bound, val, typ, parserHints); err != nil {
+				return err
+			}
+		}
+	}
+	if parserHints.Has("mustExist") || parserHints.Has("mustBeFile") || parserHints.Has("mustBeDir") {
+		if err := validatePath(val, typ, parserHints); err != nil {
+			return err
+		}
+	}
+	if parserHints.Has("port") {
+		if err := validatePort(val, typ, parserHints); err != nil {
+			return err
+		}
+	}
+	for _, bound := range []string{"minLen", "maxLen"} {
+		if parserHints.Has(bound) {
+			if err := validateLength(bound, val, parserHints); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// elements returns the values a constraint applies to: the elements of a slice, or the value itself
+func elements(val any, typ reflect.Type) []reflect.Value {
+	rv := reflect.ValueOf(val)
+	if typ.Kind() != reflect.Slice || !rv.IsValid() {
+		return []reflect.Value{rv}
+	}
+	values := make([]reflect.Value, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i)
+	}
+	return values
+}
+
+// parseConstraint parses the entries of the constraint hint key with the parser for typ, so that constraints are
+// compared as typed values rather than text.  The constraint itself is left out of the hints passed to the parser.
+func (pc *PatchPanel) parseConstraint(key string, entries []string, typ reflect.Type, parserHints Hints) ([]reflect.Value, error) {
+	parserFunc, ok := pc.parser(typ)
+	if !ok {
+		return nil, UnhandledParserTypeError{Msg: fmt.Sprintf("unknown type for parser: %v", typ)}
+	}
+
+	entryHints := make(Hints, len(parserHints))
+	for hintKey, hint := range parserHints {
+		if hintKey != key {
+			entryHints[hintKey] = hint
+		}
+	}
+	var values []reflect.Value
+	for _, entry := range entries {
+		val, err := parserFunc(entry, entryHints)
+		if err != nil {
+			return nil, fmt.Errorf("%s entry %q: %w", key, entry, err)
+		}
+		values = append(values, elements(val, typ)...)
+	}
+	return values, nil
+}
+
+// validateOneOf checks the value against the `oneof:"debug·info·warn·error"` hint.  Allowed values are parsed
+// with the field's own parser, so e.g. "0x10" and "16" are the same int.
+func (pc *PatchPanel) validateOneOf(val any, typ reflect.Type, parserHints Hints) error {
+	allowed, err := parserHints.GetList("oneof", pc.tokenSeparator)
+	if err != nil {
+		return err
+	}
+	allowedValues, err := pc.parseConstraint("oneof", allowed, typ, parserHints)
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range elements(val, typ) {
+		found := false
+		for _, allowedVal := range allowedValues {
+			if reflect.DeepEqual(elem.Interface(), allowedVal.Interface()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ValidationError{Msg: fmt.Sprintf("%v is not one of %s", elem.Interface(), strings.Join(allowed, ", "))}
+		}
+	}
+	return nil
+}
+
+// validatePattern checks string values against the `pattern:"^[a-z0-9-]+$"` hint.  The expression is unanchored,
+// as with regexp.MatchString.
+func (pc *PatchPanel) validatePattern(val any, typ reflect.Type, parserHints Hints) error {
+	expr, err := parserHints.GetString("pattern")
+	if err != nil {
+		return err
+	}
+	re, err := pc.pattern(expr)
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range elements(val, typ) {
+		if elem.Kind() != reflect.String {
+			return UnhandledParserTypeError{Msg: fmt.Sprintf("pattern hint is not supported for %v", elem.Type())}
+		}
+		if !re.MatchString(elem.String()) {
+			return ValidationError{Msg: fmt.Sprintf("%q does not match pattern %s", elem.String(), expr)}
+		}
+	}
+	return nil
+}
+
+// pattern returns the compiled regexp for a pattern hint, compiling it on first use
+func (pc *PatchPanel) pattern(expr string) (*regexp.Regexp, error) {
+	pc.Lock()
+	defer pc.Unlock()
+
+	if re, ok := pc.patterns[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("pattern hint: %w", err)
+	}
+	if pc.patterns == nil {
+		pc.patterns = make(map[string]*regexp.Regexp)
+	}
+	pc.patterns[expr] = re
+	return re, nil
+}
+
+// validateBound checks the value against an inclusive `min` or `max` hint, e.g. `min:"1s" max:"1m"`.  Bounds are
+// parsed with the field's own parser and compared as ints, uints, floats, durations or times.
+func (pc *PatchPanel) validateBound(bound string, val any, typ reflect.Type, parserHints Hints) error {
+	limit, err := parserHints.GetString(bound)
+	if err != nil {
+		return err
+	}
+	limits, err := pc.parseConstraint(bound, []string{limit}, typ, parserHints)
+	if err != nil {
+		return err
+	}
+	if len(limits) != 1 {
+		return fmt.Errorf("%s hint %q must be a single value", bound, limit)
+	}
+
+	for _, elem := range elements(val, typ) {
+		cmp, ok := compareValues(elem, limits[0])
+		if !ok {
+			return UnhandledParserTypeError{Msg: fmt.Sprintf("%s hint is not supported for %v", bound, elem.Type())}
+		}
+		if (bound == "min" && cmp < 0) || (bound == "max" && cmp > 0) {
+			return RangeError{
+				Msg:   fmt.Sprintf("%v is out of range, %s is %s", elem.Interface(), bound, limit),
+				Bound: bound,
+				Limit: limit,
+			}
+		}
+	}
+	return nil
+}
+
+// validatePath checks that string paths exist and are readable when any of these hints are set:
+//
+//	mustExist:"true"   the path must exist
+//	mustBeFile:"true"  the path must be a regular file
+//	mustBeDir:"true"   the path must be a directory
+func validatePath(val any, typ reflect.Type, parserHints Hints) error {
+	mustExist, err := parserHints.GetBool("mustExist")
+	if err != nil {
+		return err
+	}
+	mustBeFile, err := parserHints.GetBool("mustBeFile")
+	if err != nil {
+		return err
+	}
+	mustBeDir, err := parserHints.GetBool("mustBeDir")
+	if err != nil {
+		return err
+	}
+	if !mustExist && !mustBeFile && !mustBeDir {
+		return nil
+	}
+	if mustBeFile && mustBeDir {
+		return errors.New("mustBeFile and mustBeDir parser hints are mutually exclusive")
+	}
+
+	for _, elem := range elements(val, typ) {
+		if elem.Kind() != reflect.String {
+			return UnhandledParserTypeError{Msg: fmt.Sprintf("path hints are not supported for %v", elem.Type())}
+		}
+		path := elem.String()
+		info, err := os.Stat(path)
+		if err != nil {
+			return ValidationError{Msg: fmt.Sprintf("path %q must exist: %v", path, errors.Unwrap(err))}
+		}
+		switch {
+		case mustBeFile && !info.Mode().IsRegular():
+			return ValidationError{Msg: fmt.Sprintf("path %q must be a regular file, is %v", path, info.Mode().Type())}
+		case mustBeDir && !info.IsDir():
+			return ValidationError{Msg: fmt.Sprintf("path %q must be a directory", path)}
+		}
+
+		// opening anything other than a file or directory, such as a FIFO, could block
+		if info.Mode().IsRegular() || info.IsDir() {
+			f, err := os.Open(path)
+			if err != nil {
+				return ValidationError{Msg: fmt.Sprintf("path %q must be readable: %v", path, errors.Unwrap(err))}
+			}
+			f.Close()
+		}
+	}
+	return nil
+}
+
+// validatePort checks integer values against the `port` hint: `port:"true"` requires 1-65535, and
+// `port:"unprivileged"` also rejects privileged ports below 1024.
+func validatePort(val any, typ reflect.Type, parserHints Hints) error {
+	mode, err := parserHints.GetString("port")
+	if err != nil {
+		return err
+	}
+	var low int64
+	switch strings.ToLower(mode) {
+	case "true":
+		low = 1
+	case "unprivileged":
+		low = 1024
+	case "false":
+		return nil
+	default:
+		return fmt.Errorf(`port parser hint must be "true", "unprivileged" or "false", got %q`, mode)
+	}
+
+	for _, elem := range elements(val, typ) {
+		var inRange bool
+		switch elem.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			inRange = elem.Int() >= low && elem.Int() <= 65535
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			inRange = elem.Uint() >= uint64(low) && elem.Uint() <= 65535
+		default:
+			return UnhandledParserTypeError{Msg: fmt.Sprintf("port hint is not supported for %v", elem.Type())}
+		}
+		if !inRange {
+			limit := fmt.Sprintf("%d-65535", low)
+			return RangeError{Msg: fmt.Sprintf("port %v is out of range %s", elem.Interface(), limit), Bound: "port", Limit: limit}
+		}
+	}
+	return nil
+}
+
+// validateLength checks the value against an inclusive `minLen` or `maxLen` hint.  Strings are measured in
+// characters, and slices, arrays and maps in entries.
+func validateLength(bound string, val any, parserHints Hints) error {
+	limit, err := parserHints.GetInt(bound)
+	if err != nil {
+		return err
+	}
+	if limit < 0 {
+		return fmt.Errorf("%s parser hint must not be negative, got %d", bound, limit)
+	}
+
+	var length int
+	switch rv := reflect.ValueOf(val); rv.Kind() {
+	case reflect.String:
+		length = utf8.RuneCountInString(rv.String())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		length = rv.Len()
+	default:
+		return UnhandledParserTypeError{Msg: fmt.Sprintf("%s hint is not supported for %T", bound, val)}
+	}
+
+	if (bound == "minLen" && length < limit) || (bound == "maxLen" && length > limit) {
+		return RangeError{
+			Msg:   fmt.Sprintf("length %d is out of range, %s is %d", length, bound, limit),
+			Bound: bound,
+			Limit: strconv.Itoa(limit),
+		}
+	}
+	return nil
+}
+
+// compareValues orders two values of the same ordered type, reporting false for types that have no order
+func compareValues(a, b reflect.Value) (int, bool) {
+	if a.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float()), true
+	default:
+		return 0, false
+	}
+}
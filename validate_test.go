@@ -0,0 +1,322 @@
+package patchpanel
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type OneOfStruct struct {
+	Level      string        `default:"info" oneof:"debug·info·warn·error"`
+	BadLevel   string        `default:"verbose" oneof:"debug·info·warn·error"`
+	Workers    int           `default:"0x10" oneof:"8·16·32"`
+	BadWorkers int           `default:"12" oneof:"8·16·32"`
+	Interval   time.Duration `default:"60s" oneof:"1m·5m"`
+	Levels     []string      `default:"info,warn" sep:"," oneof:"debug·info·warn·error"`
+	BadLevels  []string      `default:"info,trace" sep:"," oneof:"debug·info·warn·error"`
+	BadEntry   int           `default:"1" oneof:"1·one"`
+}
+
+func Test_oneOfHint(t *testing.T) {
+	pp := New()
+	os := ToReflectType(OneOfStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantErr   bool
+	}{
+		{name: "allowed string", fieldName: "Level", want: "info"},
+		{name: "disallowed string", fieldName: "BadLevel", wantErr: true},
+		{name: "allowed int in another base", fieldName: "Workers", want: 16},
+		{name: "disallowed int", fieldName: "BadWorkers", wantErr: true},
+		{name: "equal duration", fieldName: "Interval", want: time.Minute},
+		{name: "allowed elements", fieldName: "Levels", want: []string{"info", "warn"}},
+		{name: "disallowed element", fieldName: "BadLevels", wantErr: true},
+		{name: "unparsable entry", fieldName: "BadEntry", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, os, []string{"oneof", "sep"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, err := pp.GetDefault("BadLevel", os, []string{"oneof"})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Msg != "verbose is not one of debug, info, warn, error" {
+		t.Errorf("GetDefault() error = %v, want a ValidationError listing permitted values", err)
+	}
+}
+
+type BoundStruct struct {
+	Port     int           `default:"8080" min:"1024" max:"65535"`
+	LowPort  int           `default:"80" min:"1024" max:"65535"`
+	Ratio    float64       `default:"1.5" max:"1"`
+	Timeout  time.Duration `default:"30s" min:"1s" max:"1m"`
+	Short    time.Duration `default:"500ms" min:"1s"`
+	Start    time.Time     `default:"2025-06-01T00:00:00Z" min:"2025-01-01T00:00:00Z"`
+	Early    time.Time     `default:"2024-06-01T00:00:00Z" min:"2025-01-01T00:00:00Z"`
+	Workers  uint8         `default:"8" max:"8"`
+	Retries  []int         `default:"1·2·10" max:"5"`
+	Name     string        `default:"app" min:"a"`
+	BadBound int           `default:"1" min:"one"`
+}
+
+func Test_boundHints(t *testing.T) {
+	pp := New()
+	bs := ToReflectType(BoundStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantBound string
+		wantErr   bool
+	}{
+		{name: "int in range", fieldName: "Port", want: 8080},
+		{name: "int below min", fieldName: "LowPort", wantBound: "min", wantErr: true},
+		{name: "float above max", fieldName: "Ratio", wantBound: "max", wantErr: true},
+		{name: "duration in range", fieldName: "Timeout", want: 30 * time.Second},
+		{name: "duration below min", fieldName: "Short", wantBound: "min", wantErr: true},
+		{name: "time after min", fieldName: "Start", want: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "time before min", fieldName: "Early", wantBound: "min", wantErr: true},
+		{name: "inclusive max", fieldName: "Workers", want: uint8(8)},
+		{name: "slice element above max", fieldName: "Retries", wantBound: "max", wantErr: true},
+		{name: "unordered type", fieldName: "Name", wantErr: true},
+		{name: "unparsable bound", fieldName: "BadBound", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, bs, []string{"min", "max"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var rangeErr RangeError
+			if errors.As(err, &rangeErr) != (tt.wantBound != "") || rangeErr.Bound != tt.wantBound {
+				t.Errorf("GetDefault() error = %v, want RangeError on %q", err, tt.wantBound)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type PatternStruct struct {
+	Slug     string   `default:"billing-api" pattern:"^[a-z0-9-]+$"`
+	BadSlug  string   `default:"Billing API" pattern:"^[a-z0-9-]+$"`
+	Hosts    []string `default:"a.example.com·b.example.org" pattern:"\\.example\\.com$"`
+	Port     int      `default:"80" pattern:"^8"`
+	BadRegex string   `default:"x" pattern:"("`
+}
+
+func Test_patternHint(t *testing.T) {
+	pp := New()
+	ps := ToReflectType(PatternStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      any
+		wantErr   bool
+	}{
+		{name: "matching string", fieldName: "Slug", want: "billing-api"},
+		{name: "mismatched string", fieldName: "BadSlug", wantErr: true},
+		{name: "mismatched element", fieldName: "Hosts", wantErr: true},
+		{name: "non-string type", fieldName: "Port", wantErr: true},
+		{name: "invalid expression", fieldName: "BadRegex", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pp.GetDefault(tt.fieldName, ps, []string{"pattern"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDefault() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	first, _ := pp.pattern("^[a-z0-9-]+$")
+	second, _ := pp.pattern("^[a-z0-9-]+$")
+	if first != second {
+		t.Errorf("pattern() recompiled a cached expression")
+	}
+}
+
+type LengthStruct struct {
+	ID       string   `default:"héllo" minLen:"5" maxLen:"5"`
+	ShortID  string   `default:"ab" minLen:"3"`
+	Tokens   []string `default:"a·b·c" maxLen:"2"`
+	Empty    []int    `default:"" minLen:"1"`
+	Key      [16]byte `default:"6ba7b810-9dad-11d1-80b4-00c04fd430c8" minLen:"16"`
+	Port     int      `default:"80" maxLen:"2"`
+	Negative string   `default:"a" minLen:"-1"`
+}
+
+func Test_lengthHints(t *testing.T) {
+	pp := New()
+	ls := ToReflectType(LengthStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		wantBound string
+		wantErr   bool
+	}{
+		{name: "string length in characters", fieldName: "ID"},
+		{name: "short string", fieldName: "ShortID", wantBound: "minLen", wantErr: true},
+		{name: "long slice", fieldName: "Tokens", wantBound: "maxLen", wantErr: true},
+		{name: "empty slice", fieldName: "Empty", wantBound: "minLen", wantErr: true},
+		{name: "array", fieldName: "Key"},
+		{name: "unsupported type", fieldName: "Port", wantErr: true},
+		{name: "negative limit", fieldName: "Negative", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pp.GetDefault(tt.fieldName, ls, []string{"minLen", "maxLen"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var rangeErr RangeError
+			if errors.As(err, &rangeErr) != (tt.wantBound != "") || rangeErr.Bound != tt.wantBound {
+				t.Errorf("GetDefault() error = %v, want RangeError on %q", err, tt.wantBound)
+			}
+		})
+	}
+}
+
+type PortStruct struct {
+	Listen     int    `default:"8080" port:"true"`
+	Zero       int    `default:"0" port:"true"`
+	TooHigh    uint32 `default:"70000" port:"true"`
+	Privileged uint16 `default:"443" port:"unprivileged"`
+	Metrics    uint16 `default:"9090" port:"unprivileged"`
+	Disabled   int    `default:"0" port:"false"`
+	Backends   []int  `default:"8080·99999" port:"true"`
+	Host       string `default:"localhost" port:"true"`
+	BadMode    int    `default:"80" port:"sometimes"`
+}
+
+func Test_portHint(t *testing.T) {
+	pp := New()
+	ps := ToReflectType(PortStruct{})
+
+	tests := []struct {
+		name      string
+		fieldName string
+		wantRange bool
+		wantErr   bool
+	}{
+		{name: "valid port", fieldName: "Listen"},
+		{name: "zero", fieldName: "Zero", wantRange: true, wantErr: true},
+		{name: "above 65535", fieldName: "TooHigh", wantRange: true, wantErr: true},
+		{name: "privileged port rejected", fieldName: "Privileged", wantRange: true, wantErr: true},
+		{name: "unprivileged port", fieldName: "Metrics"},
+		{name: "check disabled", fieldName: "Disabled"},
+		{name: "invalid element", fieldName: "Backends", wantRange: true, wantErr: true},
+		{name: "non-integer type", fieldName: "Host", wantErr: true},
+		{name: "invalid mode", fieldName: "BadMode", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pp.GetDefault(tt.fieldName, ps, []string{"port"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if errors.As(err, new(RangeError)) != tt.wantRange {
+				t.Errorf("GetDefault() error = %v, want RangeError %v", err, tt.wantRange)
+			}
+		})
+	}
+}
+
+func Test_pathHints(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "tls.crt")
+	if err := os.WriteFile(file, []byte("cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.crt")
+
+	tests := []struct {
+		name    string
+		value   string
+		tag     reflect.StructTag
+		wantErr bool
+	}{
+		{name: "existing file", value: file, tag: `mustExist:"true"`},
+		{name: "missing path", value: missing, tag: `mustExist:"true"`, wantErr: true},
+		{name: "regular file", value: file, tag: `mustBeFile:"true"`},
+		{name: "directory is not a file", value: dir, tag: `mustBeFile:"true"`, wantErr: true},
+		{name: "directory", value: dir, tag: `mustBeDir:"true"`},
+		{name: "file is not a directory", value: file, tag: `mustBeDir:"true"`, wantErr: true},
+		{name: "missing directory", value: missing, tag: `mustBeDir:"true"`, wantErr: true},
+		{name: "disabled", value: missing, tag: `mustExist:"false"`},
+		{name: "conflicting hints", value: file, tag: `mustBeFile:"true" mustBeDir:"true"`, wantErr: true},
+		{name: "invalid hint", value: file, tag: `mustExist:"maybe"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp := New()
+			hints := fieldHints(reflect.StructField{Tag: tt.tag})
+			_, err := pp.coerce(tt.value, reflect.TypeOf(""), hints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("coerce() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// each entry of a slice is checked
+	certs := file + TokenSeparator + missing
+	hints := fieldHints(reflect.StructField{Tag: `mustBeFile:"true"`})
+	if _, err := New().coerce(certs, reflect.TypeOf([]string{}), hints); err == nil {
+		t.Errorf("coerce() accepted a missing file in a list")
+	}
+}
+
+func TestPatchPanel_PopulateErrMsg(t *testing.T) {
+	pp := New()
+	var conf struct {
+		Port    int    `default:"80" port:"unprivileged" errmsg:"PORT must be a number between 1024 and 65535"`
+		Workers int    `default:"many" errmsg:"WORKERS must be a number"`
+		Name    string `default:"app" errmsg:"unused"`
+	}
+	err := pp.Populate(&conf)
+
+	var msgErrs []MessageError
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var msgErr MessageError
+		if errors.As(e, &msgErr) {
+			msgErrs = append(msgErrs, msgErr)
+		}
+	}
+	if len(msgErrs) != 2 {
+		t.Fatalf("Populate() error = %v, want two MessageErrors", err)
+	}
+	if msgErrs[0].Msg != "PORT must be a number between 1024 and 65535" || !errors.As(msgErrs[0], new(RangeError)) {
+		t.Errorf("Populate() Port error = %v, want the errmsg wrapping a RangeError", msgErrs[0])
+	}
+	if msgErrs[1].Msg != "WORKERS must be a number" {
+		t.Errorf("Populate() Workers error = %v", msgErrs[1])
+	}
+	if conf.Name != "app" {
+		t.Errorf("Populate() Name = %v", conf.Name)
+	}
+}